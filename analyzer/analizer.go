@@ -2,402 +2,144 @@ package analyzer
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
-)
-
-// CreateAction función principal que parsea un comando
-func CreateAction(command string) (ParsedAction, error) {
-	if strings.TrimSpace(command) == "" {
-		return ParsedAction{}, fmt.Errorf("comando vacío")
-	}
-
-	parser := NewParser(command)
-	return parser.parseComando()
-}
-
-// ParsedAction representa la estructura del comando parseado
-type ParsedAction struct {
-	Verbo    string
-	Palabras []string
-	Fecha    string
-	Hora     string
-	Type     string // "evento" o "recordatorio"
-}
-
-// Parser representa el analizador sintáctico
-type Parser struct {
-	tokens []string
-	pos    int
-}
-
-// NewParser crea un nuevo parser
-func NewParser(input string) *Parser {
-	tokens := tokenize(input)
-	return &Parser{
-		tokens: tokens,
-		pos:    0,
-	}
-}
-
-// tokenize divide la entrada en tokens
-func tokenize(input string) []string {
-	// Normalizar espacios y dividir por espacios
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return []string{}
-	}
 
-	// Dividir por espacios, manteniendo las palabras juntas
-	fields := strings.Fields(input)
-	return fields
-}
-
-// peek devuelve el token actual sin consumirlo
-func (p *Parser) peek() string {
-	if p.pos >= len(p.tokens) {
-		return ""
-	}
-	return p.tokens[p.pos]
-}
-
-// consume devuelve el token actual y avanza la posición
-func (p *Parser) consume() string {
-	if p.pos >= len(p.tokens) {
-		return ""
-	}
-	token := p.tokens[p.pos]
-	p.pos++
-	return token
-}
-
-// expect verifica que el token actual coincida con el esperado
-func (p *Parser) expect(expected string) bool {
-	if p.peek() == expected {
-		p.consume()
-		return true
-	}
-	return false
-}
-
-// hasMore verifica si quedan tokens por procesar
-func (p *Parser) hasMore() bool {
-	return p.pos < len(p.tokens)
-}
-
-// parseComando analiza la regla COMANDO → VERBO PALABRAS TIEMPO
-func (p *Parser) parseComando() (ParsedAction, error) {
-	var action ParsedAction
-
-	// Parsear VERBO
-	verbo, err := p.parseVerbo()
-	if err != nil {
-		return action, err
-	}
-	action.Verbo = verbo
-
-	// Determinar tipo basado en el verbo
-	switch verbo {
-	case "agendá":
-		action.Type = "evento"
-	case "anotá":
-		action.Type = "recordatorio"
-	case "recordame":
-		action.Type = "recordatorio"
-	default:
-		return action, fmt.Errorf("verbo inválido: '%s'. Esperado: agendá, anotá, recordame", verbo)
-	}
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/parser"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+)
 
-	// Parsear PALABRAS
-	palabras, err := p.parsePalabras()
-	if err != nil {
-		return action, err
-	}
-	action.Palabras = palabras
+// AnalyzerError es el error estructurado que devuelve el analizador, con el
+// código de error, la posición del problema y los tokens esperados.
+type AnalyzerError = parser.AnalyzerError
 
-	// Parsear TIEMPO (puede ser ε - vacío)
-	fecha, hora, err := p.parseTiempo()
-	if err != nil {
-		return action, err
+// CreateAction función principal que parsea un comando. Es el único punto de
+// entrada al analizador: tanto la API como la CLI lo usan, de modo que ambas
+// aceptan exactamente el mismo lenguaje. Si hay errores devuelve el primero,
+// siempre como *AnalyzerError; Analyze devuelve la lista completa.
+func CreateAction(command string) (*ast.Comando, error) {
+	comando, errs := Analyze(command)
+	if len(errs) > 0 {
+		return nil, errs[0]
 	}
-	action.Fecha = fecha
-	action.Hora = hora
-
-	// Verificar que no queden tokens sin procesar
-	if p.hasMore() {
-		return action, fmt.Errorf("tokens inesperados al final: %v", p.tokens[p.pos:])
-	}
-
-	return action, nil
+	return comando, nil
 }
 
-// parseVerbo analiza la regla VERBO → "agendá" | "anotá" | "recordame"
-func (p *Parser) parseVerbo() (string, error) {
-	token := p.peek()
-	switch token {
-	case "agendá", "anotá", "recordame":
-		return p.consume(), nil
-	default:
-		return "", fmt.Errorf("verbo inválido: '%s'. Esperado: agendá, anotá, recordame", token)
-	}
-}
+// Correction describe una palabra clave corregida automáticamente
+type Correction = parser.Correction
 
-// parsePalabras analiza la regla PALABRAS → PALABRA { PALABRA }
-func (p *Parser) parsePalabras() ([]string, error) {
-	var palabras []string
+// Normalization describe una fecha inexistente ajustada en modo permisivo
+type Normalization = parser.Normalization
 
-	// Debe haber al menos una palabra
-	palabra, err := p.parsePalabra()
-	if err != nil {
-		return nil, err
-	}
-	palabras = append(palabras, palabra)
+// Options configura el análisis de un comando
+type Options struct {
+	// AutoCorrect corrige verbos, meses y días mal escritos cuando la
+	// sugerencia más parecida no es ambigua
+	AutoCorrect bool
 
-	// Consumir palabras adicionales hasta encontrar tiempo o fin
-	for p.hasMore() {
-		// Verificar si el siguiente token es parte del tiempo
-		if p.esTiempo() {
-			break
-		}
+	// StrictAccents exige las tildes de las palabras clave. Por defecto se
+	// ignoran tildes y mayúsculas ("agenda", "MAÑANA", "miercoles")
+	StrictAccents bool
 
-		palabra, err := p.parsePalabra()
-		if err != nil {
-			break // No es una palabra válida, podría ser tiempo
-		}
-		palabras = append(palabras, palabra)
-	}
-
-	return palabras, nil
-}
-
-// parsePalabra analiza la regla PALABRA → [A-Za-zÁÉÍÓÚÑáéíóúñüÜ]+
-func (p *Parser) parsePalabra() (string, error) {
-	token := p.peek()
-	if token == "" {
-		return "", fmt.Errorf("se esperaba una palabra")
-	}
+	// Locale es el idioma del comando ("es", "en", "en-US"). Vacío o no
+	// soportado usa el español
+	Locale string
 
-	// Verificar que la palabra contenga solo caracteres válidos
-	matched, _ := regexp.MatchString("^[A-Za-zÁÉÍÓÚÑáéíóúñüÜ]+$", token)
-	if !matched {
-		return "", fmt.Errorf("palabra inválida: '%s'", token)
-	}
-
-	return p.consume(), nil
+	// Lenient ajusta las fechas inexistentes ("31 de febrero") al calendario
+	// en lugar de rechazarlas, y explica el ajuste en Result.Normalizations
+	Lenient bool
 }
 
-// esTiempo verifica si el token actual podría ser parte del tiempo
-func (p *Parser) esTiempo() bool {
-	token := p.peek()
-
-	// Verificar fechas fijas
-	fechasFijas := []string{"hoy", "mañana", "lunes", "martes", "miércoles",
-		"jueves", "viernes", "sábado", "domingo"}
-	for _, fecha := range fechasFijas {
-		if token == fecha {
-			return true
-		}
-	}
-
-	// Verificar si es un número (para fechas como "15 de enero 2024")
-	if esNumero(token) {
-		return true
-	}
-
-	// Verificar si empieza con "a" (para "a las")
-	if token == "a" {
-		return true
-	}
+// Result es el resultado completo del análisis de un comando
+type Result struct {
+	// Text es el texto del comando. En AnalyzeBatch es la parte de la
+	// entrada que le corresponde; los offsets de los errores siguen siendo
+	// los de la entrada completa
+	Text           string
+	Comando        *ast.Comando
+	Corrections    []Correction
+	Normalizations []Normalization
+	Errors         []*AnalyzerError
 
-	return false
+	// Warnings son las palabras que parecen palabras clave mal escritas y
+	// quedaron en la descripción porque la autocorrección está desactivada
+	Warnings []*AnalyzerError
 }
 
-// parseTiempo analiza la regla TIEMPO → ( FECHA [ HORA ] ) | HORA | ε
-func (p *Parser) parseTiempo() (string, string, error) {
-	if !p.hasMore() {
-		return "", "", nil // ε (vacío)
-	}
-
-	// Intentar parsear FECHA primero
-	fecha, err := p.parseFecha()
-	if err == nil {
-		// Si hay fecha, intentar parsear hora opcional
-		hora, _ := p.parseHora()
-		return fecha, hora, nil
-	}
-
-	// Si no hay fecha, intentar parsear solo HORA
-	hora, err := p.parseHora()
-	if err == nil {
-		return "", hora, nil
-	}
-
-	// Si no hay fecha ni hora, es ε (vacío)
-	return "", "", nil
+// Analyze parsea un comando y devuelve todos los errores encontrados. El
+// parser se recupera de cada error, así que un comando con varios problemas
+// los reporta todos de una vez.
+func Analyze(command string) (*ast.Comando, []*AnalyzerError) {
+	result := AnalyzeWithOptions(command, Options{})
+	return result.Comando, result.Errors
 }
 
-// parseFecha analiza la regla FECHA → FECHA_FIJA | NUMERO "de" MES AÑO
-func (p *Parser) parseFecha() (string, error) {
-	// Intentar fecha fija primero
-	fechaFija, err := p.parseFechaFija()
-	if err == nil {
-		return fechaFija, nil
-	}
-
-	// Intentar formato "NUMERO de MES AÑO"
-	if !esNumero(p.peek()) {
-		return "", fmt.Errorf("fecha inválida")
-	}
-
-	numero := p.consume()
-
-	if !p.expect("de") {
-		p.pos-- // Retroceder
-		return "", fmt.Errorf("se esperaba 'de' después del número")
-	}
-
-	mes, err := p.parseMes()
-	if err != nil {
-		return "", err
+// AnalyzeWithOptions parsea un comando con las opciones indicadas
+func AnalyzeWithOptions(command string, opts Options) Result {
+	vocabulario := vocab.Get(opts.Locale)
+	if strings.TrimSpace(command) == "" {
+		return Result{Errors: []*AnalyzerError{parser.NewAnalyzerError(command, parser.CodigoComandoVacio, 0, len(command), command,
+			[]string{lexer.VERBO}, i18n.T(vocabulario.Locale, "comando vacío"))}}
 	}
 
-	año, err := p.parseAño()
+	p := nuevoParser(command, vocabulario, opts)
+	comando, err := p.Parse()
 	if err != nil {
-		return "", err
+		return Result{Text: command, Corrections: p.Corrections(), Normalizations: p.Normalizations(), Errors: p.Errors(), Warnings: p.Warnings()}
 	}
-
-	return numero + " de " + mes + " " + año, nil
+	return Result{Text: command, Comando: comando, Corrections: p.Corrections(), Normalizations: p.Normalizations(), Warnings: p.Warnings()}
 }
 
-// parseFechaFija analiza fechas fijas como "hoy", "mañana", días de la semana
-func (p *Parser) parseFechaFija() (string, error) {
-	token := p.peek()
-
-	fechasFijas := []string{"hoy", "mañana", "lunes", "martes", "miércoles",
-		"jueves", "viernes", "sábado", "domingo"}
-
-	for _, fecha := range fechasFijas {
-		if token == fecha {
-			return p.consume(), nil
-		}
-	}
-
-	return "", fmt.Errorf("fecha fija inválida: '%s'", token)
-}
-
-// parseHora analiza la regla HORA → "a las" NUMERO ":" MINUTOS (formato 24h)
-func (p *Parser) parseHora() (string, error) {
-	// Verificar que hay suficientes tokens antes de comenzar
-	if p.pos+2 >= len(p.tokens) {
-		return "", fmt.Errorf("tokens insuficientes para hora")
-	}
-
-	// Verificar que los próximos tokens son "a" y "las"
-	if p.tokens[p.pos] != "a" || p.tokens[p.pos+1] != "las" {
-		return "", fmt.Errorf("se esperaba 'a las'")
-	}
-
-	// Verificar que hay un token de hora después de "a las"
-	if p.pos+2 >= len(p.tokens) {
-		return "", fmt.Errorf("se esperaba hora después de 'a las'")
-	}
-
-	horaToken := p.tokens[p.pos+2]
-
-	// Validar el formato de la hora antes de consumir tokens
-	re := regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?$`)
-	matches := re.FindStringSubmatch(horaToken)
-	if matches == nil {
-		return "", fmt.Errorf("formato de hora inválido: '%s'", horaToken)
-	}
-
-	// Validar rangos
-	h, _ := strconv.Atoi(matches[1])
-	if h < 0 || h > 23 {
-		return "", fmt.Errorf("hora fuera de rango: %d", h)
+// AnalyzeBatch parsea una entrada con uno o varios comandos, separados por
+// "y", comas, puntos y comas, puntos, conjunciones ("luego") o saltos de
+// línea antes de otro verbo: "agendá dentista el lunes a las 9 y recordame
+// comprar pan el martes". Devuelve el resultado de cada comando en orden; un
+// comando con errores no impide analizar los demás.
+func AnalyzeBatch(command string, opts Options) []Result {
+	vocabulario := vocab.Get(opts.Locale)
+	if strings.TrimSpace(command) == "" {
+		return []Result{AnalyzeWithOptions(command, opts)}
 	}
 
-	m := 0
-	if matches[2] != "" {
-		m, _ = strconv.Atoi(matches[2])
-		if m < 0 || m > 59 {
-			return "", fmt.Errorf("minutos fuera de rango: %d", m)
-		}
+	p := nuevoParser(command, vocabulario, opts)
+	var results []Result
+	for _, s := range p.ParseSentencias() {
+		results = append(results, Result{
+			Text:           command[s.Inicio:s.Fin],
+			Comando:        s.Comando,
+			Corrections:    s.Corrections,
+			Normalizations: s.Normalizations,
+			Errors:         s.Errors,
+			Warnings:       s.Warnings,
+		})
 	}
-
-	// Si llegamos aquí, todo es válido - consumir tokens
-	p.consume() // "a"
-	p.consume() // "las"
-	p.consume() // hora
-
-	return fmt.Sprintf("a las %02d:%02d", h, m), nil
+	return results
 }
 
-// parseMes analiza los nombres de meses
-func (p *Parser) parseMes() (string, error) {
-	token := p.peek()
-	meses := []string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
-		"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
-
-	for _, mes := range meses {
-		if token == mes {
-			return p.consume(), nil
-		}
-	}
-
-	return "", fmt.Errorf("mes inválido: '%s'", token)
+// nuevoParser crea el parser de la entrada con las opciones del análisis
+func nuevoParser(command string, vocabulario *vocab.Vocabulary, opts Options) *parser.Parser {
+	l := lexer.NewWithOptions(command, lexer.Options{StrictAccents: opts.StrictAccents, Vocabulary: vocabulario})
+	return parser.NewWithOptions(l, parser.Options{AutoCorrect: opts.AutoCorrect, Lenient: opts.Lenient})
 }
 
-// parseAño analiza la regla AÑO → DIGITO DIGITO DIGITO DIGITO
-func (p *Parser) parseAño() (string, error) {
-	año := p.consume()
-	if len(año) != 4 {
-		return "", fmt.Errorf("año debe tener 4 dígitos: '%s'", año)
-	}
-
-	for _, char := range año {
-		if char < '0' || char > '9' {
-			return "", fmt.Errorf("año inválido: '%s'", año)
-		}
+// TipoAccion determina el tipo de acción según el verbo, con el mapeo definido
+// en el vocabulario español. Un verbo sin tipo conocido crea un recordatorio.
+// Para otros idiomas el parser ya resuelve el tipo en ast.Verbo.Tipo.
+func TipoAccion(verbo string) string {
+	if tipo, ok := vocab.Current().VerbType(verbo); ok {
+		return tipo
 	}
-
-	return año, nil
-}
-
-// parseMinutos analiza la regla MINUTOS → DIGITO DIGITO
-func (p *Parser) parseMinutos() (string, error) {
-	minutos := p.consume()
-	if len(minutos) != 2 {
-		return "", fmt.Errorf("minutos deben tener 2 dígitos: '%s'", minutos)
-	}
-
-	for _, char := range minutos {
-		if char < '0' || char > '9' {
-			return "", fmt.Errorf("minutos inválidos: '%s'", minutos)
-		}
-	}
-
-	// Validar que los minutos estén en rango válido
-	mins, _ := strconv.Atoi(minutos)
-	if mins >= 60 {
-		return "", fmt.Errorf("minutos fuera de rango: '%s'", minutos)
-	}
-
-	return minutos, nil
+	return "recordatorio"
 }
 
-func esNumero(token string) bool {
-	if token == "" {
-		return false
+// TipoVerbo devuelve el tipo de acción de un verbo ya analizado
+func TipoVerbo(verbo *ast.Verbo) string {
+	if verbo.Tipo != "" {
+		return verbo.Tipo
 	}
-
-	for _, char := range token {
-		if char < '0' || char > '9' {
-			return false
-		}
-	}
-	return true
+	return TipoAccion(verbo.Value)
 }
 
 // Ejemplos de uso
@@ -412,6 +154,7 @@ func Ejemplo() {
 		"agendá reunión miércoles a las 15:30",
 		"anotá ejercicio jueves a las 06:45",
 		"recordame descanso viernes a las 23:15",
+		"programá cita con Juan 12 de mayo de 2025 a las 15",
 
 		"agendá llamada sábado a las", //Invalido
 		"",                            //invalido
@@ -426,24 +169,20 @@ func Ejemplo() {
 		fmt.Printf("Comando: '%s'\n", ejemplo)
 
 		// Parsear comando
-		parsedAction, err := CreateAction(ejemplo)
+		comando, err := CreateAction(ejemplo)
 		if err != nil {
 			fmt.Printf("Error parseando: %s\n", err)
 			fmt.Println("---")
 			continue
 		}
 
-		fmt.Printf("✓ Parseado - Verbo: %s, Palabras: %v, Fecha: '%s', Hora: '%s', Tipo: '%s'\n",
-			parsedAction.Verbo, parsedAction.Palabras, parsedAction.Fecha, parsedAction.Hora, parsedAction.Type)
-
 		// Transformar a Action final
-		action, err := TransformToAction(parsedAction, userName)
+		action, err := TransformToAction(comando, userName)
 		if err != nil {
 			fmt.Printf("Error transformando: %s\n", err)
 		} else {
-			fmt.Printf("✓ Transformado - Usuario: %s, Descripción: '%s', Fecha: %s\n",
-				action.UserName, action.Description, action.Date.Format("2006-01-02 15:04"))
-			fmt.Println(action.Date)
+			fmt.Printf("✓ Transformado - Usuario: %s, Descripción: '%s', Tipo: '%s', Fecha: %s\n",
+				action.UserName, action.Description, action.Type, action.Date.Format("2006-01-02 15:04"))
 		}
 		fmt.Println("---")
 	}
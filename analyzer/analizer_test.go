@@ -0,0 +1,366 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/models"
+)
+
+// ahora es la hora de referencia de las pruebas: miércoles 15 de octubre de
+// 2025 a las 12:00 en UTC
+var ahora = time.Date(2025, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+// transformar analiza el comando y lo convierte en acción con el reloj
+// detenido en ahora
+func transformar(t *testing.T, command string, opts TransformOptions) (models.Action, []Warning, error) {
+	t.Helper()
+	comando, err := CreateAction(command)
+	if err != nil {
+		t.Fatalf("%q: error de análisis: %v", command, err)
+	}
+	if opts.Clock == nil {
+		opts.Clock = FixedClock(ahora)
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return TransformToActionWithOptions(comando, "usuario_test", opts)
+}
+
+// fecha escribe la fecha en el formato de las pruebas
+func fecha(t time.Time) string {
+	return t.Format("2006-01-02 15:04")
+}
+
+func TestCreateAction(t *testing.T) {
+	casos := []struct {
+		command     string
+		descripcion string
+		tipo        string
+		fecha       string
+	}{
+		{"agendá reunión hoy", "reunión", "evento", "2025-10-15 00:00"},
+		{"recordame comprar leche mañana a las 10:30", "comprar leche", "recordatorio", "2025-10-16 10:30"},
+		{"agendá cita médica lunes a las 14:00", "cita médica", "evento", "2025-10-20 14:00"},
+		{"programá cita con Juan 12 de mayo de 2026 a las 15", "cita con Juan", "evento", "2026-05-12 15:00"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.command, func(t *testing.T) {
+			action, _, err := transformar(t, c.command, TransformOptions{})
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if action.UserName != "usuario_test" || action.Description != c.descripcion || action.Type != c.tipo {
+				t.Errorf("acción = %q %q %q, se esperaba %q %q", action.UserName, action.Description, action.Type,
+					c.descripcion, c.tipo)
+			}
+			if got := fecha(action.Date); got != c.fecha {
+				t.Errorf("fecha = %s, se esperaba %s", got, c.fecha)
+			}
+		})
+	}
+}
+
+func TestCreateActionError(t *testing.T) {
+	for _, command := range []string{"", "comando inválido", "agendá", "agendá reunión a las 25:00"} {
+		t.Run(command, func(t *testing.T) {
+			comando, err := CreateAction(command)
+			if err == nil {
+				t.Fatalf("se esperaba un error, se obtuvo %+v", comando)
+			}
+			if _, ok := err.(*AnalyzerError); !ok {
+				t.Errorf("el error es %T, se esperaba *AnalyzerError", err)
+			}
+		})
+	}
+}
+
+func TestTipoAccion(t *testing.T) {
+	casos := []struct {
+		command string
+		locale  string
+		tipo    string
+	}{
+		{"agendá reunión", "es", "evento"},
+		{"organizar fiesta", "es", "evento"},
+		{"recordame pagar la luz", "es", "recordatorio"},
+		{"anotá comprar pan", "es", "nota"},
+		{"registrar gastos", "es", "nota"},
+		{"registrá gastos", "es", "nota"},
+		{"tengo que estudiar", "es", "tarea"},
+		{"debo llamar a Ana", "es", "tarea"},
+		{"schedule meeting", "en", "evento"},
+		{"write down groceries", "en", "nota"},
+		{"jot down groceries", "en", "nota"},
+		{"note the plate number", "en", "nota"},
+		{"i have to study", "en", "tarea"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.command, func(t *testing.T) {
+			result := AnalyzeWithOptions(c.command, Options{Locale: c.locale})
+			if len(result.Errors) > 0 {
+				t.Fatalf("error inesperado: %v", result.Errors)
+			}
+			action, err := TransformToAction(result.Comando, "usuario_test")
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if action.Type != c.tipo {
+				t.Errorf("tipo = %q, se esperaba %q", action.Type, c.tipo)
+			}
+		})
+	}
+
+	// Un verbo que no está en el vocabulario crea un recordatorio
+	if tipo := TipoAccion("inventado"); tipo != "recordatorio" {
+		t.Errorf("TipoAccion(inventado) = %q", tipo)
+	}
+}
+
+func TestHora(t *testing.T) {
+	casos := []struct {
+		command    string
+		fecha      string
+		recurrence string
+	}{
+		{"recordame algo hoy a las 3 pm", "2025-10-15 15:00", ""},
+		{"recordame algo hoy a las 6 menos cuarto de la tarde", "2025-10-15 17:45", ""},
+		{"recordame algo hoy a las 8 y 10 de la noche", "2025-10-15 20:10", ""},
+		{"recordame algo mañana al mediodía", "2025-10-16 12:00", ""},
+		// La medianoche es el final del día nombrado
+		{"agendá fiesta el viernes a la medianoche", "2025-10-18 00:00", ""},
+		{"recordame algo hoy a las 12 de la noche", "2025-10-16 00:00", ""},
+		{"agendá fiesta todos los viernes a la medianoche", "2025-10-18 00:00", "DTSTART:20251018T000000Z\nRRULE:FREQ=WEEKLY;BYDAY=SA"},
+		// Las 0:00 y las 12 am son el comienzo del día
+		{"agendá fiesta el viernes a las 0:00", "2025-10-17 00:00", ""},
+		{"agendá fiesta el viernes a las 12 am", "2025-10-17 00:00", ""},
+	}
+
+	for _, c := range casos {
+		t.Run(c.command, func(t *testing.T) {
+			action, _, err := transformar(t, c.command, TransformOptions{})
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if got := fecha(action.Date); got != c.fecha {
+				t.Errorf("fecha = %s, se esperaba %s", got, c.fecha)
+			}
+			if action.Recurrence != c.recurrence {
+				t.Errorf("repetición = %q, se esperaba %q", action.Recurrence, c.recurrence)
+			}
+		})
+	}
+}
+
+func TestFechaRelativa(t *testing.T) {
+	casos := []struct {
+		command string
+		fecha   string
+	}{
+		{"agendá algo pasado mañana", "2025-10-17 00:00"},
+		{"agendá algo en 3 días", "2025-10-18 00:00"},
+		{"agendá algo dentro de 2 semanas", "2025-10-29 00:00"},
+		{"agendá algo dentro de dos semanas", "2025-10-29 00:00"},
+		{"agendá algo en 2 meses", "2025-12-15 00:00"},
+		{"agendá algo la semana que viene", "2025-10-22 00:00"},
+		{"agendá algo el mes próximo", "2025-11-15 00:00"},
+		{"agendá algo este viernes", "2025-10-17 00:00"},
+		{"agendá algo el próximo viernes", "2025-10-24 00:00"},
+		{"agendá algo el viernes que viene", "2025-10-24 00:00"},
+		// "este" es el de esta semana aunque sea hoy; "el próximo" es el de la semana siguiente
+		{"agendá algo este miércoles", "2025-10-15 00:00"},
+		{"agendá algo el próximo miércoles", "2025-10-22 00:00"},
+		{"agendá algo fin de mes", "2025-10-31 00:00"},
+		{"agendá algo fin de año", "2025-12-31 00:00"},
+		{"agendá algo pasado mañana a las 9", "2025-10-17 09:00"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.command, func(t *testing.T) {
+			action, _, err := transformar(t, c.command, TransformOptions{})
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if got := fecha(action.Date); got != c.fecha {
+				t.Errorf("fecha = %s, se esperaba %s", got, c.fecha)
+			}
+		})
+	}
+}
+
+func TestNumerosEscritos(t *testing.T) {
+	casos := []struct {
+		command string
+		locale  string
+		fecha   string
+	}{
+		{"recordame pagar el alquiler el cinco de mayo de 2026 a las diez y cuarto", "es", "2026-05-05 10:15"},
+		{"agendá algo el primero de junio de dos mil veintiséis", "es", "2026-06-01 00:00"},
+		{"agendá algo el treinta y uno de diciembre a las once quince", "es", "2025-12-31 11:15"},
+		{"agendá algo en tres días a las doce menos cuarto", "es", "2025-10-18 11:45"},
+		{"schedule meeting on march twenty first at nine", "en", "2026-03-21 09:00"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.command, func(t *testing.T) {
+			result := AnalyzeWithOptions(c.command, Options{Locale: c.locale})
+			if len(result.Errors) > 0 {
+				t.Fatalf("error inesperado: %v", result.Errors)
+			}
+			action, _, err := TransformToActionWithOptions(result.Comando, "usuario_test",
+				TransformOptions{Clock: FixedClock(ahora), Location: time.UTC})
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if got := fecha(action.Date); got != c.fecha {
+				t.Errorf("fecha = %s, se esperaba %s", got, c.fecha)
+			}
+		})
+	}
+}
+
+func TestFechaEspecifica(t *testing.T) {
+	casos := []struct {
+		command string
+		fecha   string
+	}{
+		// Sin año es la próxima vez que ocurre la fecha
+		{"agendá algo 15 de marzo", "2026-03-15 00:00"},
+		{"agendá algo 20 de octubre", "2025-10-20 00:00"},
+		{"agendá algo 15 de marzo de 2026", "2026-03-15 00:00"},
+		{"agendá algo 15 de marzo 2026", "2026-03-15 00:00"},
+		{"agendá algo 15/03", "2026-03-15 00:00"},
+		{"agendá algo 15/03/2026", "2026-03-15 00:00"},
+		{"agendá algo 2026-03-15", "2026-03-15 00:00"},
+		// Sólo el día es este mes, o el siguiente si ya pasó
+		{"agendá algo el 15", "2025-10-15 00:00"},
+		{"agendá algo el 20", "2025-10-20 00:00"},
+		{"agendá algo el 10", "2025-11-10 00:00"},
+		{"agendá algo el 31", "2025-10-31 00:00"},
+		{"agendá algo 29 de febrero", "2028-02-29 00:00"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.command, func(t *testing.T) {
+			action, _, err := transformar(t, c.command, TransformOptions{})
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if got := fecha(action.Date); got != c.fecha {
+				t.Errorf("fecha = %s, se esperaba %s", got, c.fecha)
+			}
+		})
+	}
+}
+
+func TestFechaPermisiva(t *testing.T) {
+	command := "agendá algo 31 de febrero 2026"
+	if _, errs := Analyze(command); len(errs) != 1 || errs[0].Code != "INVALID_DATE" {
+		t.Fatalf("errores = %v, se esperaba INVALID_DATE", errs)
+	}
+
+	result := AnalyzeWithOptions(command, Options{Lenient: true})
+	if len(result.Errors) > 0 {
+		t.Fatalf("error inesperado: %v", result.Errors)
+	}
+	if len(result.Normalizations) != 1 || result.Normalizations[0].Normalized != "3 de marzo 2026" {
+		t.Errorf("normalizaciones = %+v", result.Normalizations)
+	}
+	action, _, err := TransformToActionWithOptions(result.Comando, "usuario_test",
+		TransformOptions{Clock: FixedClock(ahora), Location: time.UTC})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if got := fecha(action.Date); got != "2026-03-03 00:00" {
+		t.Errorf("fecha = %s, se esperaba 2026-03-03 00:00", got)
+	}
+}
+
+func TestDetalleAccion(t *testing.T) {
+	action, _, err := transformar(t, "agendá reunión con Laura y Pedro en la oficina sobre el presupuesto mañana", TransformOptions{})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if action.Description != "reunión con Laura y Pedro en la oficina sobre el presupuesto" {
+		t.Errorf("descripción = %q", action.Description)
+	}
+	if !slices.Equal(action.Participants, []string{"Laura", "Pedro"}) || action.Location != "la oficina" || action.Topic != "el presupuesto" {
+		t.Errorf("participantes = %q, lugar = %q, tema = %q", action.Participants, action.Location, action.Topic)
+	}
+}
+
+func TestPrioridadYEtiquetas(t *testing.T) {
+	casos := []struct {
+		command     string
+		descripcion string
+		prioridad   string
+		etiquetas   []string
+	}{
+		{"agendá reunión urgente mañana #trabajo", "reunión", "urgente", []string{"trabajo"}},
+		{"recordame pagar la luz prioridad alta #casa #Facturas", "pagar la luz", "alta", []string{"casa", "facturas"}},
+		{"anotá comprar pan sin apuro", "comprar pan", "baja", nil},
+		{"anotá revisar PR #42", "revisar PR #42", "", nil},
+	}
+
+	for _, c := range casos {
+		action, _, err := transformar(t, c.command, TransformOptions{})
+		if err != nil {
+			t.Fatalf("%q: error inesperado: %v", c.command, err)
+		}
+		var etiquetas []string
+		for _, tag := range action.Tags {
+			etiquetas = append(etiquetas, tag.Name)
+		}
+		if action.Description != c.descripcion || action.Priority != c.prioridad || !slices.Equal(etiquetas, c.etiquetas) {
+			t.Errorf("%q: descripción = %q, prioridad = %q, etiquetas = %q", c.command, action.Description, action.Priority, etiquetas)
+		}
+	}
+}
+
+func TestDescripcion(t *testing.T) {
+	casos := []struct {
+		command     string
+		descripcion string
+	}{
+		{"recordame comprar 2 kilos de pan mañana", "comprar 2 kilos de pan"},
+		{"anotá revisar PR #42", "revisar PR #42"},
+		{"anotá comprar 1/2 kilo", "comprar 1/2 kilo"},
+		{"recordame pagar Netflix (familia) el 10", "pagar Netflix (familia)"},
+		{`anotá "llamar a las 10 al 0800" mañana`, "llamar a las 10 al 0800"},
+	}
+
+	for _, c := range casos {
+		action, _, err := transformar(t, c.command, TransformOptions{})
+		if err != nil {
+			t.Fatalf("%q: error inesperado: %v", c.command, err)
+		}
+		if action.Description != c.descripcion {
+			t.Errorf("%q: descripción = %q, se esperaba %q", c.command, action.Description, c.descripcion)
+		}
+	}
+}
+
+func TestTiempoAntesDelVerbo(t *testing.T) {
+	casos := []struct {
+		command string
+		fecha   string
+	}{
+		{"mañana a las 10 agendá dentista", "2025-10-16 10:00"},
+		{"agendá el lunes reunión a las 9", "2025-10-20 09:00"},
+		{"agendá mañana reunión con Ana", "2025-10-16 00:00"},
+	}
+
+	for _, c := range casos {
+		action, _, err := transformar(t, c.command, TransformOptions{})
+		if err != nil {
+			t.Fatalf("%q: error inesperado: %v", c.command, err)
+		}
+		if got := fecha(action.Date); got != c.fecha {
+			t.Errorf("%q: fecha = %s, se esperaba %s", c.command, got, c.fecha)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package analyzer
+
+import "time"
+
+// Clock da la hora de referencia con que se resuelven las fechas relativas
+// ("hoy", "mañana", "el viernes"). Permite reproducir un análisis en otro
+// momento y escribir pruebas deterministas.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapta una función a Clock
+type ClockFunc func() time.Time
+
+// Now devuelve el resultado de la función
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock es el reloj del sistema
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock devuelve un reloj detenido en t
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
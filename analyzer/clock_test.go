@@ -0,0 +1,43 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReloj(t *testing.T) {
+	if got := FixedClock(ahora).Now(); !got.Equal(ahora) {
+		t.Errorf("FixedClock.Now() = %v, se esperaba %v", got, ahora)
+	}
+	llamadas := 0
+	reloj := ClockFunc(func() time.Time { llamadas++; return ahora })
+	if reloj.Now(); llamadas != 1 {
+		t.Errorf("ClockFunc llamó %d veces a la función", llamadas)
+	}
+
+	// El mismo comando con otra hora de referencia da otra fecha
+	casos := []struct {
+		now   time.Time
+		fecha string
+	}{
+		{ahora, "2025-10-17 00:00"},
+		{time.Date(2025, time.October, 18, 9, 0, 0, 0, time.UTC), "2025-10-24 00:00"},
+		{time.Date(2024, time.February, 28, 9, 0, 0, 0, time.UTC), "2024-03-01 00:00"},
+	}
+	for _, c := range casos {
+		action, _, err := transformar(t, "agendá algo el viernes", TransformOptions{Clock: FixedClock(c.now)})
+		if err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+		if got := fecha(action.Date); got != c.fecha {
+			t.Errorf("now = %s: fecha = %s, se esperaba %s", fecha(c.now), got, c.fecha)
+		}
+	}
+
+	// Un reloj detenido da siempre el mismo resultado
+	primera, _, _ := transformar(t, "recordame algo en 3 días a las 10", TransformOptions{})
+	segunda, _, _ := transformar(t, "recordame algo en 3 días a las 10", TransformOptions{})
+	if !primera.Date.Equal(segunda.Date) {
+		t.Errorf("fechas distintas con el mismo reloj: %v y %v", primera.Date, segunda.Date)
+	}
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/parser"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+	"github.com/RodrigoGonzalez78/go_analyzer/models"
+)
+
+// duracionMinutos devuelve cuántos minutos dura la acción según el rango de
+// horas ("de 10 a 11:30") o la duración ("durante 2 horas") del comando; 0
+// si no se indicó. Un rango que cruza la medianoche termina al día siguiente
+// ("de 22 a 1" son 3 horas; ver parser.RangeMinutes).
+func duracionMinutos(v *vocab.Vocabulary, tiempo *ast.Tiempo) (int, error) {
+	switch {
+	case tiempo.HoraFin != nil && tiempo.Hora != nil:
+		hi, mi, err := horaCanonica(v, tiempo.Hora)
+		if err != nil {
+			return 0, err
+		}
+		hf, mf, err := horaCanonica(v, tiempo.HoraFin)
+		if err != nil {
+			return 0, err
+		}
+		minutos, ok := parser.RangeMinutes(hi*60+mi, hf*60+mf)
+		switch {
+		case ok:
+			return minutos, nil
+		case hi == hf && mi == mf:
+			return 0, i18n.Errorf(v.Locale, "la hora de fin (%s) es igual a la de inicio", fmt.Sprintf("%02d:%02d", hf, mf))
+		default:
+			// Un rango como "de 11 a 10" no llega desde el parser, que ya lo
+			// rechazó, pero sí en un AST construido por otro cliente
+			return 0, i18n.Errorf(v.Locale, "la hora de fin (%s) debe ser posterior a la de inicio (%s)",
+				fmt.Sprintf("%02d:%02d", hf, mf), fmt.Sprintf("%02d:%02d", hi, mi))
+		}
+	case tiempo.Duracion != nil:
+		return tiempo.Duracion.Minutos, nil
+	}
+	return 0, nil
+}
+
+// aplicarDuracion completa la duración y la fecha de fin de la acción a
+// partir de su fecha de inicio
+func aplicarDuracion(action *models.Action, minutos int) {
+	if minutos == 0 {
+		return
+	}
+	fin := action.Date.Add(time.Duration(minutos) * time.Minute)
+	action.Duration = minutos
+	action.EndDate = &fin
+}
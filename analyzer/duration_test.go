@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+)
+
+func TestDuracion(t *testing.T) {
+	casos := []struct {
+		command  string
+		fecha    string
+		fin      string // vacío si la acción no tiene fin
+		duracion int
+	}{
+		{"agendá reunión mañana de 10:00 a 11:30", "2025-10-16 10:00", "2025-10-16 11:30", 90},
+		{"agendá reunión mañana de 2 a 4 de la tarde", "2025-10-16 14:00", "2025-10-16 16:00", 120},
+		{"agendá fiesta el sábado de 20 a medianoche", "2025-10-18 20:00", "2025-10-19 00:00", 240},
+		{"agendá guardia el sábado de 22 a 1", "2025-10-18 22:00", "2025-10-19 01:00", 180},
+		{"agendá guardia mañana de 23:30 a 0:15", "2025-10-16 23:30", "2025-10-17 00:15", 45},
+		{"agendá guardia mañana de 20 a 8", "2025-10-16 20:00", "2025-10-17 08:00", 12 * 60},
+		{"agendá reunión mañana a las 10 durante 2 horas", "2025-10-16 10:00", "2025-10-16 12:00", 120},
+		{"agendá reunión mañana a las 10 por hora y media", "2025-10-16 10:00", "2025-10-16 11:30", 90},
+		{"agendá viaje mañana durante 3 días", "2025-10-16 00:00", "2025-10-19 00:00", 3 * 24 * 60},
+		{"agendá reunión mañana a las 10", "2025-10-16 10:00", "", 0},
+	}
+
+	for _, c := range casos {
+		t.Run(c.command, func(t *testing.T) {
+			action, _, err := transformar(t, c.command, TransformOptions{})
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if got := fecha(action.Date); got != c.fecha {
+				t.Errorf("fecha = %s, se esperaba %s", got, c.fecha)
+			}
+			fin := ""
+			if action.EndDate != nil {
+				fin = fecha(*action.EndDate)
+			}
+			if fin != c.fin || action.Duration != c.duracion {
+				t.Errorf("fin = %q (%d min), se esperaba %q (%d min)", fin, action.Duration, c.fin, c.duracion)
+			}
+		})
+	}
+}
+
+func TestRangoInvalido(t *testing.T) {
+	// Rangos armados a mano, sin pasar por el parser
+	casos := []struct {
+		nombre      string
+		inicio, fin int
+	}{
+		{"sin duración", 10, 10},
+		{"fin anterior", 11, 10},
+		{"fin anterior a la tarde", 12, 10},
+	}
+
+	for _, c := range casos {
+		tiempo := &ast.Tiempo{Hora: &ast.Hora{Hora: c.inicio}, HoraFin: &ast.Hora{Hora: c.fin}}
+		if _, err := duracionMinutos(vocab.Default(), tiempo); err == nil {
+			t.Errorf("%s: se esperaba un error para el rango de %d a %d", c.nombre, c.inicio, c.fin)
+		}
+	}
+}
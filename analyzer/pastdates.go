@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+)
+
+// PastDatePolicy define qué hacer cuando la fecha de una acción ya pasó
+type PastDatePolicy string
+
+const (
+	// PastDatesWarn guarda la acción y devuelve una advertencia. Es la
+	// política por defecto.
+	PastDatesWarn PastDatePolicy = "warn"
+
+	// PastDatesReject rechaza la acción con un *PastDateError
+	PastDatesReject PastDatePolicy = "reject"
+
+	// PastDatesRoll mueve la acción a la próxima vez que ocurre la fecha
+	// cuando el comando lo permite: mañana si sólo se indicó la hora o "hoy",
+	// la semana que viene para "este viernes" y el año que viene para una
+	// fecha sin año. Las fechas que no se pueden mover ("ayer", "15 de marzo
+	// 2020") se rechazan.
+	PastDatesRoll PastDatePolicy = "roll"
+)
+
+// Códigos de las advertencias y errores sobre fechas pasadas
+const (
+	CodigoFechaPasada = "PAST_DATE"
+	CodigoFechaMovida = "DATE_ROLLED"
+)
+
+// formatoFechaMensaje es el formato de las fechas en los mensajes
+const formatoFechaMensaje = "2006-01-02 15:04"
+
+// ParsePastDatePolicy valida el nombre de una política. Vacío es la política
+// por defecto.
+func ParsePastDatePolicy(s string) (PastDatePolicy, bool) {
+	switch PastDatePolicy(s) {
+	case "":
+		return PastDatesWarn, true
+	case PastDatesWarn, PastDatesReject, PastDatesRoll:
+		return PastDatePolicy(s), true
+	}
+	return "", false
+}
+
+// Warning es una advertencia sobre una acción que igualmente se guardó
+type Warning struct {
+	Code    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// PastDateError indica que la fecha de la acción ya pasó y la política no
+// permite guardarla
+type PastDateError struct {
+	Date    time.Time
+	Message string
+}
+
+func (e *PastDateError) Error() string {
+	return e.Message
+}
+
+// aplicarPolitica revisa si la fecha resuelta ya pasó respecto de now y
+// aplica la política. Devuelve la fecha a guardar y, si corresponde, una
+// advertencia.
+func aplicarPolitica(politica PastDatePolicy, locale string, fecha *ast.Fecha, hora *ast.Hora,
+	resultado, now time.Time) (time.Time, *Warning, error) {
+	if !fechaPasada(resultado, hora != nil, now) {
+		return resultado, nil, nil
+	}
+
+	escrita := resultado.Format(formatoFechaMensaje)
+	switch politica {
+	case PastDatesReject:
+		return time.Time{}, nil, &PastDateError{Date: resultado, Message: i18n.Sprintf(locale, "la fecha %s ya pasó", escrita)}
+	case PastDatesRoll:
+		siguiente, ok := adelantar(fecha, resultado, now)
+		if !ok {
+			return time.Time{}, nil, &PastDateError{Date: resultado,
+				Message: i18n.Sprintf(locale, "la fecha %s ya pasó y no se puede mover", escrita)}
+		}
+		return siguiente, &Warning{Code: CodigoFechaMovida,
+			Message: i18n.Sprintf(locale, "la fecha %s ya pasó; se usó %s", escrita, siguiente.Format(formatoFechaMensaje))}, nil
+	default:
+		return resultado, &Warning{Code: CodigoFechaPasada, Message: i18n.Sprintf(locale, "la fecha %s ya pasó", escrita)}, nil
+	}
+}
+
+// fechaPasada indica si la acción quedaría en el pasado. Sin hora se compara
+// sólo el día, así "hoy" no es una fecha pasada.
+func fechaPasada(resultado time.Time, conHora bool, now time.Time) bool {
+	if conHora {
+		return resultado.Before(now)
+	}
+	return resultado.Before(inicioDelDia(now))
+}
+
+// adelantar devuelve la próxima ocurrencia de una fecha que ya pasó, si el
+// comando no la fija. Conserva la hora indicada.
+func adelantar(fecha *ast.Fecha, resultado, now time.Time) (time.Time, bool) {
+	manana := inicioDelDia(now).AddDate(0, 0, 1)
+
+	var dia time.Time
+	switch {
+	case fecha == nil:
+		// Sólo la hora: si ya pasó hoy, mañana
+		dia = manana
+	case fecha.Tipo == "relativa" && fecha.Desplazamiento != nil && fecha.Desplazamiento.Cantidad == 0:
+		// "hoy a las 8" a las 20
+		dia = manana
+	case fecha.Tipo == "diasemana":
+		dia = resultado.AddDate(0, 0, 7)
+	case fecha.Tipo == "especifica" && fecha.Anio == 0 && (fecha.Mes != "" || fecha.MesNumero != 0):
+		dia = proximaFecha(manana, resultado.Month(), fecha.Numero)
+	case fecha.Tipo == "especifica" && fecha.Anio == 0:
+		dia = proximoDia(manana, fecha.Numero)
+	default:
+		return time.Time{}, false
+	}
+
+	return time.Date(dia.Year(), dia.Month(), dia.Day(), resultado.Hour(), resultado.Minute(), 0, 0, resultado.Location()), true
+}
+
+// inicioDelDia devuelve la medianoche del día de t
+func inicioDelDia(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
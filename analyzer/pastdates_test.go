@@ -0,0 +1,70 @@
+package analyzer
+
+import "testing"
+
+func TestFechasPasadas(t *testing.T) {
+	casos := []struct {
+		command  string
+		politica PastDatePolicy
+		fecha    string // vacío si la acción se rechaza
+		aviso    string // código de la advertencia, vacío si no hay
+	}{
+		{"recordame pagar hoy a las 08:00", PastDatesWarn, "2025-10-15 08:00", CodigoFechaPasada},
+		{"recordame pagar hoy a las 08:00", PastDatesReject, "", ""},
+		{"recordame pagar hoy a las 08:00", PastDatesRoll, "2025-10-16 08:00", CodigoFechaMovida},
+		{"recordame pagar a las 8", PastDatesRoll, "2025-10-16 08:00", CodigoFechaMovida},
+		{"recordame pagar este miércoles a las 9", PastDatesRoll, "2025-10-22 09:00", CodigoFechaMovida},
+		{"recordame pagar 15 de marzo 2020", PastDatesWarn, "2020-03-15 00:00", CodigoFechaPasada},
+		{"recordame pagar 15 de marzo 2020", PastDatesRoll, "", ""},
+		{"recordame pagar ayer", PastDatesWarn, "2025-10-14 00:00", CodigoFechaPasada},
+		{"recordame pagar ayer", PastDatesRoll, "", ""},
+		// Sin hora, hoy no es una fecha pasada
+		{"recordame pagar hoy", PastDatesReject, "2025-10-15 00:00", ""},
+		{"recordame pagar hoy a las 18", PastDatesReject, "2025-10-15 18:00", ""},
+	}
+
+	for _, c := range casos {
+		t.Run(string(c.politica)+" "+c.command, func(t *testing.T) {
+			action, warnings, err := transformar(t, c.command, TransformOptions{PastDates: c.politica})
+			if c.fecha == "" {
+				if _, ok := err.(*PastDateError); !ok {
+					t.Fatalf("error = %v, se esperaba *PastDateError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if got := fecha(action.Date); got != c.fecha {
+				t.Errorf("fecha = %s, se esperaba %s", got, c.fecha)
+			}
+			aviso := ""
+			if len(warnings) > 0 {
+				aviso = warnings[0].Code
+			}
+			if len(warnings) > 1 || aviso != c.aviso {
+				t.Errorf("advertencias = %v, se esperaba %q", warnings, c.aviso)
+			}
+		})
+	}
+}
+
+func TestParsePastDatePolicy(t *testing.T) {
+	casos := []struct {
+		nombre   string
+		politica PastDatePolicy
+		ok       bool
+	}{
+		{"", PastDatesWarn, true},
+		{"warn", PastDatesWarn, true},
+		{"reject", PastDatesReject, true},
+		{"roll", PastDatesRoll, true},
+		{"ignorar", "", false},
+	}
+
+	for _, c := range casos {
+		if politica, ok := ParsePastDatePolicy(c.nombre); politica != c.politica || ok != c.ok {
+			t.Errorf("ParsePastDatePolicy(%q) = %q, %v", c.nombre, politica, ok)
+		}
+	}
+}
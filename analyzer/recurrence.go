@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/rrule"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+	"github.com/RodrigoGonzalez78/go_analyzer/models"
+)
+
+// frecuencias traduce las unidades de tiempo a frecuencias RRULE
+var frecuencias = map[string]rrule.Frequency{
+	vocab.UnitDay:   rrule.Daily,
+	vocab.UnitWeek:  rrule.Weekly,
+	vocab.UnitMonth: rrule.Monthly,
+	vocab.UnitYear:  rrule.Yearly,
+}
+
+// reglaRecurrencia convierte el nodo de recurrencia en una regla RRULE. Los
+// días de la semana se buscan en el vocabulario v.
+func reglaRecurrencia(v *vocab.Vocabulary, recurrencia *ast.Recurrencia) (rrule.Rule, error) {
+	frecuencia, ok := frecuencias[recurrencia.Unidad]
+	if !ok {
+		return rrule.Rule{}, i18n.Errorf(v.Locale, "unidad de repetición no soportada: %s", recurrencia.Unidad)
+	}
+
+	if recurrencia.Intervalo > rrule.MaxInterval {
+		return rrule.Rule{}, i18n.Errorf(v.Locale, "el intervalo de la repetición no puede superar %d", rrule.MaxInterval)
+	}
+
+	regla := rrule.Rule{Freq: frecuencia, Interval: recurrencia.Intervalo, ByMonthDay: recurrencia.DiaDelMes}
+	for _, dia := range recurrencia.Dias {
+		numero, ok := v.WeekdayNumber(dia)
+		if !ok {
+			return rrule.Rule{}, i18n.Errorf(v.Locale, "día de la semana inválido: %s", dia)
+		}
+		regla.ByDay = append(regla.ByDay, time.Weekday(numero))
+	}
+	return regla, nil
+}
+
+// primeraOcurrencia devuelve la regla de la recurrencia y su primera
+// ocurrencia. La fecha del comando, si la hay, es desde cuándo se repite; si
+// no, desde hoy. La primera ocurrencia nunca es anterior a now (o a hoy si no
+// se indicó la hora), por eso las recurrencias no pasan por la política de
+// fechas pasadas.
+func primeraOcurrencia(v *vocab.Vocabulary, tiempo *ast.Tiempo, now time.Time) (rrule.Rule, time.Time, error) {
+	regla, err := reglaRecurrencia(v, tiempo.Recurrencia)
+	if err != nil {
+		return rrule.Rule{}, time.Time{}, err
+	}
+	if tiempo.Hora != nil && esMedianoche(v, tiempo.Hora) {
+		// "todos los viernes a la medianoche" es al final de cada viernes
+		regla = diaSiguiente(regla)
+	}
+
+	inicio := inicioDelDia(now)
+	if tiempo.Fecha != nil || tiempo.Hora != nil {
+		inicio, err = parseDateAndTime(v, tiempo.Fecha, tiempo.Hora, now)
+		if err != nil {
+			return rrule.Rule{}, time.Time{}, err
+		}
+	}
+
+	desde := inicioDelDia(now)
+	if tiempo.Hora != nil {
+		desde = now
+	}
+	if inicio.After(desde) {
+		desde = inicio
+	}
+	if tiempo.Fecha == nil {
+		// Sin fecha el intervalo se cuenta desde el primer día que coincide:
+		// "cada 2 lunes" empieza el próximo lunes
+		cadaUno := regla
+		cadaUno.Interval = 0
+		inicio = cadaUno.First(inicio, desde)
+	}
+	return regla, regla.First(inicio, desde), nil
+}
+
+// diaSiguiente corre al día siguiente los días de la semana y el día del mes
+// de la regla. El día 31 no se corre: el mes siguiente no siempre tiene un 32.
+func diaSiguiente(regla rrule.Rule) rrule.Rule {
+	dias := make([]time.Weekday, len(regla.ByDay))
+	for i, dia := range regla.ByDay {
+		dias[i] = (dia + 1) % 7
+	}
+	regla.ByDay = dias
+	if regla.ByMonthDay > 0 && regla.ByMonthDay < 31 {
+		regla.ByMonthDay++
+	}
+	return regla
+}
+
+// Occurrences devuelve las ocurrencias de la acción entre desde (inclusive)
+// y hasta (exclusivo), con la fecha en la zona loc. Una acción que no se
+// repite es su única ocurrencia si cae en el rango. Las ocurrencias de una
+// acción recurrente comparten el ID de la acción y se calculan en la zona en
+// que se creó (el TZID de la regla), aunque se muestren en loc. Una regla sin
+// DTSTART se repite en loc.
+func Occurrences(action models.Action, desde, hasta time.Time, loc *time.Location) ([]models.Action, error) {
+	inicio := action.Date.In(loc)
+	if action.Recurrence == "" {
+		if inicio.Before(desde) || !inicio.Before(hasta) {
+			return nil, nil
+		}
+		action.Date = inicio
+		if action.EndDate != nil {
+			fin := action.EndDate.In(loc)
+			action.EndDate = &fin
+		}
+		return []models.Action{action}, nil
+	}
+
+	recurrencia, err := rrule.ParseRecurrence(action.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	if !recurrencia.Start.IsZero() {
+		inicio = action.Date.In(recurrencia.Start.Location())
+	}
+	var ocurrencias []models.Action
+	for _, fecha := range recurrencia.Rule.Between(inicio, desde, hasta) {
+		fecha = fecha.In(loc)
+		ocurrencia := action
+		ocurrencia.Date = fecha
+		if action.EndDate != nil {
+			fin := fecha.Add(time.Duration(action.Duration) * time.Minute)
+			ocurrencia.EndDate = &fin
+		}
+		ocurrencias = append(ocurrencias, ocurrencia)
+	}
+	return ocurrencias, nil
+}
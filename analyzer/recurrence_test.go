@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/models"
+)
+
+func TestRecurrencia(t *testing.T) {
+	casos := []struct {
+		command    string
+		fecha      string
+		recurrence string
+	}{
+		{"recordame tomar la pastilla todos los días a las 8:00", "2025-10-16 08:00", "DTSTART:20251016T080000Z\nRRULE:FREQ=DAILY"},
+		{"recordame tomar la pastilla todos los días a las 18:00", "2025-10-15 18:00", "DTSTART:20251015T180000Z\nRRULE:FREQ=DAILY"},
+		{"agendá gimnasio todos los lunes y miércoles a las 19", "2025-10-15 19:00", "DTSTART:20251015T190000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE"},
+		{"agendá reunión cada 2 lunes", "2025-10-20 00:00", "DTSTART:20251020T000000Z\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO"},
+		{"recordame pagar cada 15 del mes", "2025-10-15 00:00", "DTSTART:20251015T000000Z\nRRULE:FREQ=MONTHLY;BYMONTHDAY=15"},
+		{"agendá clase el 1 de diciembre cada semana a las 9", "2025-12-01 09:00", "DTSTART:20251201T090000Z\nRRULE:FREQ=WEEKLY"},
+		// Con fecha el intervalo se cuenta desde ella
+		{"agendá turno cada 2 semanas desde el 2 de octubre de 2025", "2025-10-16 00:00", "DTSTART:20251016T000000Z\nRRULE:FREQ=WEEKLY;INTERVAL=2"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.command, func(t *testing.T) {
+			action, _, err := transformar(t, c.command, TransformOptions{})
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if got := fecha(action.Date); got != c.fecha {
+				t.Errorf("fecha = %s, se esperaba %s", got, c.fecha)
+			}
+			if action.Recurrence != c.recurrence {
+				t.Errorf("repetición = %q, se esperaba %q", action.Recurrence, c.recurrence)
+			}
+		})
+	}
+}
+
+func TestIntervaloGrande(t *testing.T) {
+	if _, errs := Analyze("agendá revisión cada 500000 semanas"); len(errs) != 1 || errs[0].Code != "INVALID_RECURRENCE" {
+		t.Errorf("errores = %v, se esperaba INVALID_RECURRENCE", errs)
+	}
+
+	// Un AST armado a mano puede traer un intervalo que el parser rechaza
+	comando, err := CreateAction("agendá revisión cada 2 semanas")
+	if err != nil {
+		t.Fatalf("error de análisis: %v", err)
+	}
+	_, _, tiempo := descomponer(comando)
+	tiempo.Recurrencia.Intervalo = 500000
+
+	comienzo := time.Now()
+	if _, _, err := TransformToActionWithOptions(comando, "usuario_test", TransformOptions{Clock: FixedClock(ahora), Location: time.UTC}); err == nil {
+		t.Error("se esperaba un error por el intervalo")
+	}
+	if d := time.Since(comienzo); d > 50*time.Millisecond {
+		t.Errorf("la transformación tardó %v", d)
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	madrid := zona(t, "Europe/Madrid")
+	action, _, err := transformar(t, "agendá clase todos los lunes a las 9", TransformOptions{Location: madrid})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if action.Recurrence != "DTSTART;TZID=Europe/Madrid:20251020T090000\nRRULE:FREQ=WEEKLY;BYDAY=MO" {
+		t.Fatalf("repetición = %q", action.Recurrence)
+	}
+
+	desde := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	hasta := time.Date(2025, time.November, 4, 0, 0, 0, 0, time.UTC)
+
+	// Las ocurrencias siguen a las 9 de Madrid después del cambio de horario
+	// del 26 de octubre, aunque se consulten en UTC
+	casos := []struct {
+		accion models.Action
+		fechas []string // en UTC
+	}{
+		{action, []string{"2025-10-20 07:00", "2025-10-27 08:00", "2025-11-03 08:00"}},
+		// Una regla sin DTSTART se repite en la zona de la consulta
+		{models.Action{Date: action.Date, Recurrence: "FREQ=WEEKLY;BYDAY=MO"},
+			[]string{"2025-10-20 07:00", "2025-10-27 07:00", "2025-11-03 07:00"}},
+		{models.Action{Date: time.Date(2025, time.October, 10, 9, 0, 0, 0, time.UTC)}, []string{"2025-10-10 09:00"}},
+		{models.Action{Date: time.Date(2025, time.November, 10, 9, 0, 0, 0, time.UTC)}, nil},
+	}
+	for _, c := range casos {
+		ocurrencias, err := Occurrences(c.accion, desde, hasta, time.UTC)
+		if err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+		var fechas []string
+		for _, o := range ocurrencias {
+			if o.Date.Location() != time.UTC {
+				t.Errorf("la ocurrencia está en %s, se esperaba UTC", o.Date.Location())
+			}
+			fechas = append(fechas, fecha(o.Date))
+		}
+		if !slices.Equal(fechas, c.fechas) {
+			t.Errorf("%q: ocurrencias = %v, se esperaba %v", c.accion.Recurrence, fechas, c.fechas)
+		}
+	}
+
+	if _, err := Occurrences(models.Action{Recurrence: "FREQ=HOURLY"}, desde, hasta, time.UTC); err == nil {
+		t.Error("se esperaba un error por la regla inválida")
+	}
+}
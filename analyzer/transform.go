@@ -1,38 +1,132 @@
 package analyzer
 
 import (
-	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/rrule"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
 	"github.com/RodrigoGonzalez78/go_analyzer/models"
 )
 
-// TransformToAction convierte ParsedAction a Action para la base de datos
-func TransformToAction(parsed ParsedAction, userName string) (models.Action, error) {
+// TransformOptions configura la conversión de un comando en acción
+type TransformOptions struct {
+	// PastDates define qué hacer si la fecha de la acción ya pasó. Vacío
+	// equivale a PastDatesWarn.
+	PastDates PastDatePolicy
+
+	// Location es la zona horaria del usuario: "hoy", los días de la semana
+	// y las horas escritas se interpretan en ella. Nil usa la zona del servidor.
+	Location *time.Location
+
+	// Clock da la hora de referencia para las fechas relativas y las fechas
+	// pasadas. Nil usa SystemClock.
+	Clock Clock
+}
+
+// TransformToAction convierte el AST de un comando a Action para la base de
+// datos. Las fechas pasadas se aceptan; TransformToActionWithOptions permite
+// rechazarlas o moverlas y devuelve las advertencias.
+func TransformToAction(comando *ast.Comando, userName string) (models.Action, error) {
+	action, _, err := TransformToActionWithOptions(comando, userName, TransformOptions{})
+	return action, err
+}
+
+// TransformToActionWithOptions convierte el AST de un comando a Action
+// aplicando la zona horaria y la política de fechas pasadas. La fecha de la
+// acción se devuelve en UTC. Si la política rechaza la fecha el error es un
+// *PastDateError. Si el comando se repite, la fecha es la primera ocurrencia
+// y Recurrence guarda la regla. Con un rango de horas o una duración se
+// completan Duration y EndDate.
+func TransformToActionWithOptions(comando *ast.Comando, userName string, opts TransformOptions) (models.Action, []Warning, error) {
+	verbo, detalle, tiempo := descomponer(comando)
+	vocabulario := vocab.Get(comando.Locale)
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock.Now().In(loc)
+
 	action := models.Action{
-		UserName:    userName,
-		Description: strings.Join(parsed.Palabras, " "),
-		Type:        parsed.Type, // Agregar el tipo determinado por el analizador
+		UserName:     userName,
+		Description:  strings.Join(detalle.Palabras, " "),
+		Type:         TipoVerbo(verbo), // Agregar el tipo determinado por el analizador
+		Participants: detalle.Participantes,
+		Location:     detalle.Lugar,
+		Topic:        detalle.Tema,
+		Priority:     detalle.Prioridad,
+	}
+	for _, etiqueta := range detalle.Etiquetas {
+		action.Tags = append(action.Tags, models.Tag{Name: etiqueta})
+	}
+
+	duracion, err := duracionMinutos(vocabulario, tiempo)
+	if err != nil {
+		return action, nil, i18n.Errorf(vocabulario.Locale, "error procesando fecha/hora: %v", err)
+	}
+
+	// Una acción recurrente se guarda con su primera ocurrencia y la regla
+	if tiempo.Recurrencia != nil {
+		regla, primera, err := primeraOcurrencia(vocabulario, tiempo, now)
+		if err != nil {
+			return action, nil, i18n.Errorf(vocabulario.Locale, "error procesando fecha/hora: %v", err)
+		}
+		action.Date = primera.UTC()
+		action.Recurrence = rrule.Recurrence{Start: primera, Rule: regla}.String()
+		aplicarDuracion(&action, duracion)
+		return action, nil, nil
 	}
 
 	// Procesar fecha y hora
-	dateTime, err := parseDateAndTime(parsed.Fecha, parsed.Hora)
+	dateTime, err := parseDateAndTime(vocabulario, tiempo.Fecha, tiempo.Hora, now)
 	if err != nil {
-		return action, fmt.Errorf("error procesando fecha/hora: %v", err)
+		return action, nil, i18n.Errorf(vocabulario.Locale, "error procesando fecha/hora: %v", err)
 	}
-	action.Date = dateTime
 
-	return action, nil
+	var warnings []Warning
+	dateTime, warning, err := aplicarPolitica(opts.PastDates, vocabulario.Locale, tiempo.Fecha, tiempo.Hora, dateTime, now)
+	if err != nil {
+		return action, nil, err
+	}
+	if warning != nil {
+		warnings = append(warnings, *warning)
+	}
+	action.Date = dateTime.UTC()
+	aplicarDuracion(&action, duracion)
+
+	return action, warnings, nil
+}
+
+// descomponer extrae los nodos concretos de un comando
+func descomponer(comando *ast.Comando) (*ast.Verbo, *ast.DetalleEvento, *ast.Tiempo) {
+	verbo, _ := comando.Verbo.(*ast.Verbo)
+	if verbo == nil {
+		verbo = &ast.Verbo{}
+	}
+	detalle, _ := comando.Detalle.(*ast.DetalleEvento)
+	if detalle == nil {
+		detalle = &ast.DetalleEvento{}
+	}
+	tiempo, _ := comando.Tiempo.(*ast.Tiempo)
+	if tiempo == nil {
+		tiempo = &ast.Tiempo{}
+	}
+	return verbo, detalle, tiempo
 }
 
-// parseDateAndTime convierte fecha y hora string a time.Time
-func parseDateAndTime(fechaStr, horaStr string) (time.Time, error) {
-	now := time.Now()
+// parseDateAndTime convierte los nodos de fecha y hora a time.Time,
+// resolviendo las fechas relativas respecto de now. Los nombres de días,
+// meses y fechas relativas se buscan en el vocabulario v.
+func parseDateAndTime(v *vocab.Vocabulary, fecha *ast.Fecha, hora *ast.Hora, now time.Time) (time.Time, error) {
 
 	// Si no hay fecha ni hora, usar fecha actual
-	if fechaStr == "" && horaStr == "" {
+	if fecha == nil && hora == nil {
 		return now, nil
 	}
 
@@ -40,154 +134,245 @@ func parseDateAndTime(fechaStr, horaStr string) (time.Time, error) {
 	var targetDate time.Time
 	var err error
 
-	if fechaStr == "" {
+	if fecha == nil {
 		// Si no hay fecha, usar hoy
 		targetDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	} else {
-		targetDate, err = parseDate(fechaStr, now)
+		targetDate, err = parseDate(v, fecha, now)
 		if err != nil {
 			return time.Time{}, err
 		}
 	}
 
 	// Procesar hora
-	if horaStr == "" {
+	if hora == nil {
 		// Si no hay hora, usar 00:00
 		return targetDate, nil
 	}
 
-	hour, minute, err := parseTime(horaStr)
+	// Combinar fecha y hora
+	h, m, err := horaCanonica(v, hora)
 	if err != nil {
 		return time.Time{}, err
 	}
-
-	// Combinar fecha y hora
 	result := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(),
-		hour, minute, 0, 0, targetDate.Location())
+		h, m, 0, 0, targetDate.Location())
+	if esMedianoche(v, hora) {
+		// "el viernes a la medianoche" es el final del viernes
+		result = result.AddDate(0, 0, 1)
+	}
 
 	return result, nil
 }
 
-// parseDate convierte string de fecha a time.Time
-func parseDate(fechaStr string, now time.Time) (time.Time, error) {
-	switch fechaStr {
-	case "hoy":
-		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), nil
-	case "mañana":
-		tomorrow := now.AddDate(0, 0, 1)
-		return time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, now.Location()), nil
-	case "lunes", "martes", "miércoles", "jueves", "viernes", "sábado", "domingo":
-		return getNextWeekday(fechaStr, now), nil
-	default:
-		// Formato "15 de marzo 2024"
-		return parseFullDate(fechaStr)
+// esMedianoche indica si la hora es la medianoche que termina el día: "a la
+// medianoche" o "a las 12 de la noche". "a las 0:00" y "12 am" son el
+// comienzo del día.
+func esMedianoche(v *vocab.Vocabulary, hora *ast.Hora) bool {
+	h, m, err := horaCanonica(v, hora)
+	if err != nil || h != 0 || m != 0 {
+		return false
 	}
+	return hora.Nombre != "" || (hora.Hora == 12 && v.OvernightPeriod(hora.Periodo))
 }
 
-// getNextWeekday obtiene la próxima fecha del día de la semana especificado
-func getNextWeekday(dayName string, now time.Time) time.Time {
-	weekdays := map[string]time.Weekday{
-		"domingo":   time.Sunday,
-		"lunes":     time.Monday,
-		"martes":    time.Tuesday,
-		"miércoles": time.Wednesday,
-		"jueves":    time.Thursday,
-		"viernes":   time.Friday,
-		"sábado":    time.Saturday,
-	}
-
-	targetWeekday := weekdays[dayName]
-	currentWeekday := now.Weekday()
+// HoraCanonica convierte un nodo de hora a hora y minutos en formato 24h:
+// aplica el periodo ("3 pm" → 15:00) y los minutos coloquiales ("6 menos
+// cuarto" → 05:45). Los periodos se buscan en el vocabulario del idioma.
+func HoraCanonica(locale string, hora *ast.Hora) (int, int, error) {
+	return horaCanonica(vocab.Get(locale), hora)
+}
 
-	daysUntilTarget := int(targetWeekday - currentWeekday)
-	if daysUntilTarget <= 0 {
-		daysUntilTarget += 7 // Próxima semana
+func horaCanonica(v *vocab.Vocabulary, hora *ast.Hora) (int, int, error) {
+	h := hora.Hora
+	if hora.Periodo != "" {
+		var ok bool
+		h, ok = v.ResolveHour(hora.Periodo, hora.Hora)
+		if !ok {
+			return 0, 0, i18n.Errorf(v.Locale, "la hora %d no corresponde a '%s'", hora.Hora, hora.Periodo)
+		}
 	}
 
-	targetDate := now.AddDate(0, 0, daysUntilTarget)
-	return time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, now.Location())
+	// "12 menos cuarto de la noche" son las 23:45 del mismo día
+	total := (h*60 + hora.Minutos - hora.Menos + 24*60) % (24 * 60)
+	return total / 60, total % 60, nil
 }
 
-// parseFullDate parsea formato "15 de marzo 2024"
-func parseFullDate(fechaStr string) (time.Time, error) {
-	parts := strings.Split(fechaStr, " ")
-	if len(parts) != 4 || parts[1] != "de" {
-		return time.Time{}, fmt.Errorf("formato de fecha inválido: %s", fechaStr)
+// parseDate convierte un nodo de fecha a time.Time, resolviendo las fechas
+// relativas respecto de now
+func parseDate(v *vocab.Vocabulary, fecha *ast.Fecha, now time.Time) (time.Time, error) {
+	hoy := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch fecha.Tipo {
+	case "relativa", "desplazamiento":
+		d := fecha.Desplazamiento
+		if d == nil {
+			cantidad, unidad, ok := v.RelativeOffset(fecha.Valor)
+			if !ok {
+				return time.Time{}, i18n.Errorf(v.Locale, "fecha relativa no soportada: %s", fecha.Valor)
+			}
+			d = &ast.Desplazamiento{Cantidad: cantidad, Unidad: unidad}
+		}
+		return desplazar(hoy, d), nil
+	case "fin":
+		return finDePeriodo(hoy, fecha.Fin.Unidad), nil
+	case "diasemana":
+		return getNextWeekday(v, fecha, hoy)
+	default:
+		// Formato "15 de marzo 2024"
+		return parseFullDate(v, fecha, now)
 	}
+}
 
-	day, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return time.Time{}, fmt.Errorf("día inválido: %s", parts[0])
+// desplazar suma el desplazamiento a la fecha. Al sumar meses o años el día
+// se ajusta al último del mes si no existe (31 de enero + 1 mes → 28 de febrero).
+func desplazar(fecha time.Time, d *ast.Desplazamiento) time.Time {
+	switch d.Unidad {
+	case vocab.UnitWeek:
+		return fecha.AddDate(0, 0, 7*d.Cantidad)
+	case vocab.UnitMonth:
+		return sumarMeses(fecha, d.Cantidad)
+	case vocab.UnitYear:
+		return sumarMeses(fecha, 12*d.Cantidad)
+	default:
+		return fecha.AddDate(0, 0, d.Cantidad)
 	}
+}
 
-	month, err := parseMonth(parts[2])
-	if err != nil {
-		return time.Time{}, err
+// sumarMeses suma meses sin desbordar al mes siguiente
+func sumarMeses(fecha time.Time, meses int) time.Time {
+	primero := time.Date(fecha.Year(), fecha.Month()+time.Month(meses), 1, 0, 0, 0, 0, fecha.Location())
+	dia := min(fecha.Day(), diasDelMes(primero))
+	return time.Date(primero.Year(), primero.Month(), dia, 0, 0, 0, 0, fecha.Location())
+}
+
+// diasDelMes devuelve la cantidad de días del mes de la fecha
+func diasDelMes(fecha time.Time) int {
+	return time.Date(fecha.Year(), fecha.Month()+1, 0, 0, 0, 0, 0, fecha.Location()).Day()
+}
+
+// finDePeriodo devuelve el último día de la unidad en curso. El fin de
+// semana es el próximo sábado, u hoy si ya es fin de semana.
+func finDePeriodo(hoy time.Time, unidad string) time.Time {
+	switch unidad {
+	case vocab.UnitWeek:
+		if hoy.Weekday() == time.Sunday {
+			return hoy
+		}
+		return hoy.AddDate(0, 0, int(time.Saturday-hoy.Weekday()))
+	case vocab.UnitYear:
+		return time.Date(hoy.Year(), time.December, 31, 0, 0, 0, 0, hoy.Location())
+	default:
+		return time.Date(hoy.Year(), hoy.Month(), diasDelMes(hoy), 0, 0, 0, 0, hoy.Location())
 	}
+}
 
-	year, err := strconv.Atoi(parts[3])
-	if err != nil {
-		return time.Time{}, fmt.Errorf("año inválido: %s", parts[3])
+// getNextWeekday obtiene la fecha del día de la semana especificado. Sin
+// modificador es el próximo (nunca hoy); "este viernes" es el de esta semana
+// (puede ser hoy) y "el próximo viernes" el de la semana que viene, contando
+// las semanas de lunes a domingo.
+func getNextWeekday(v *vocab.Vocabulary, fecha *ast.Fecha, hoy time.Time) (time.Time, error) {
+	dayName := fecha.Dia
+	if dayName == "" {
+		dayName = fecha.Valor
+	}
+	day, ok := v.WeekdayNumber(dayName)
+	if !ok {
+		return time.Time{}, i18n.Errorf(v.Locale, "día de la semana inválido: %s", dayName)
 	}
 
-	return time.Date(year, month, day, 0, 0, 0, 0, time.Local), nil
-}
+	targetWeekday := time.Weekday(day)
+	currentWeekday := hoy.Weekday()
+	daysUntilTarget := int(targetWeekday - currentWeekday)
 
-// parseMonth convierte nombre de mes a time.Month
-func parseMonth(monthName string) (time.Month, error) {
-	months := map[string]time.Month{
-		"enero":      time.January,
-		"febrero":    time.February,
-		"marzo":      time.March,
-		"abril":      time.April,
-		"mayo":       time.May,
-		"junio":      time.June,
-		"julio":      time.July,
-		"agosto":     time.August,
-		"septiembre": time.September,
-		"octubre":    time.October,
-		"noviembre":  time.November,
-		"diciembre":  time.December,
-	}
-
-	month, exists := months[monthName]
-	if !exists {
-		return 0, fmt.Errorf("mes inválido: %s", monthName)
+	switch fecha.Modificador {
+	case vocab.ModifierThis:
+		if daysUntilTarget < 0 {
+			daysUntilTarget += 7
+		}
+	case vocab.ModifierNext:
+		// Lunes de la semana que viene más el día pedido
+		lunes := 8 - diaISO(currentWeekday)
+		daysUntilTarget = lunes + diaISO(targetWeekday) - 1
+	default:
+		if daysUntilTarget <= 0 {
+			daysUntilTarget += 7 // Próxima semana
+		}
 	}
 
-	return month, nil
+	return hoy.AddDate(0, 0, daysUntilTarget), nil
 }
 
-// parseTime parsea hora en formato "a las HH:MM"
-func parseTime(horaStr string) (int, int, error) {
-	// horaStr viene como "a las 15:30"
-	parts := strings.Split(horaStr, " ")
-	if len(parts) != 3 || parts[0] != "a" || parts[1] != "las" {
-		return 0, 0, fmt.Errorf("formato de hora inválido: %s", horaStr)
+// diaISO numera los días de lunes (1) a domingo (7)
+func diaISO(d time.Weekday) int {
+	if d == time.Sunday {
+		return 7
 	}
+	return int(d)
+}
 
-	timeParts := strings.Split(parts[2], ":")
-	if len(timeParts) != 2 {
-		return 0, 0, fmt.Errorf("formato de hora inválido: %s", parts[2])
+// parseFullDate convierte una fecha específica ("15 de marzo 2024", "15/03",
+// "el 15"). Si no se indicó el año se usa la próxima vez que ocurre la fecha,
+// contando hoy; si tampoco se indicó el mes, el mes en curso o el siguiente
+// que tenga ese día.
+func parseFullDate(v *vocab.Vocabulary, fecha *ast.Fecha, now time.Time) (time.Time, error) {
+	hoy := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	month := time.Month(fecha.MesNumero)
+	if fecha.Mes != "" {
+		var err error
+		month, err = parseMonth(v, fecha.Mes)
+		if err != nil {
+			return time.Time{}, err
+		}
 	}
 
-	hour, err := strconv.Atoi(timeParts[0])
-	if err != nil {
-		return 0, 0, fmt.Errorf("hora inválida: %s", timeParts[0])
+	switch {
+	case fecha.Anio != 0:
+		resultado := time.Date(fecha.Anio, month, fecha.Numero, 0, 0, 0, 0, hoy.Location())
+		if resultado.Day() != fecha.Numero || resultado.Month() != month {
+			// time.Date pasaría el 31 de abril al 1 de mayo sin avisar; el
+			// parser no deja llegar esa fecha, pero un AST propio sí puede
+			return time.Time{}, i18n.Errorf(v.Locale, "la fecha %s no existe", fecha.String())
+		}
+		return resultado, nil
+	case month != 0:
+		return proximaFecha(hoy, month, fecha.Numero), nil
+	default:
+		return proximoDia(hoy, fecha.Numero), nil
 	}
+}
 
-	minute, err := strconv.Atoi(timeParts[1])
-	if err != nil {
-		return 0, 0, fmt.Errorf("minuto inválido: %s", timeParts[1])
+// proximaFecha devuelve la próxima vez, desde hoy, que ocurre el día del mes.
+// El 29 de febrero salta al próximo año bisiesto.
+func proximaFecha(hoy time.Time, month time.Month, dia int) time.Time {
+	for anio := hoy.Year(); anio <= hoy.Year()+8; anio++ {
+		fecha := time.Date(anio, month, dia, 0, 0, 0, 0, hoy.Location())
+		if fecha.Day() == dia && !fecha.Before(hoy) {
+			return fecha
+		}
 	}
+	return time.Date(hoy.Year(), month, dia, 0, 0, 0, 0, hoy.Location())
+}
 
-	if hour < 0 || hour > 23 {
-		return 0, 0, fmt.Errorf("hora fuera de rango: %d", hour)
+// proximoDia devuelve la próxima vez, desde hoy, que ocurre el día: en este
+// mes si todavía no pasó, si no en el siguiente mes que lo tenga ("el 31")
+func proximoDia(hoy time.Time, dia int) time.Time {
+	for meses := 0; meses < 12; meses++ {
+		fecha := time.Date(hoy.Year(), hoy.Month()+time.Month(meses), dia, 0, 0, 0, 0, hoy.Location())
+		if fecha.Day() == dia && !fecha.Before(hoy) {
+			return fecha
+		}
 	}
+	return time.Date(hoy.Year(), hoy.Month(), dia, 0, 0, 0, 0, hoy.Location())
+}
 
-	if minute < 0 || minute > 59 {
-		return 0, 0, fmt.Errorf("minuto fuera de rango: %d", minute)
+// parseMonth convierte nombre de mes a time.Month
+func parseMonth(v *vocab.Vocabulary, monthName string) (time.Month, error) {
+	month, exists := v.MonthNumber(monthName)
+	if !exists {
+		return 0, i18n.Errorf(v.Locale, "mes inválido: %s", monthName)
 	}
 
-	return hour, minute, nil
+	return time.Month(month), nil
 }
@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // las pruebas no dependen de la base IANA del sistema
+)
+
+// zona carga una zona horaria IANA
+func zona(t *testing.T, nombre string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(nombre)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestZonaHoraria(t *testing.T) {
+	// A las 22 del 15 en Buenos Aires ya es el 16 en UTC
+	noche := time.Date(2025, time.October, 16, 1, 0, 0, 0, time.UTC)
+
+	casos := []struct {
+		command string
+		zona    string
+		now     time.Time
+		fecha   string // en UTC
+	}{
+		{"recordame pagar hoy a las 10", "America/Argentina/Buenos_Aires", ahora, "2025-10-15 13:00"},
+		{"recordame pagar mañana a las 9", "America/Argentina/Buenos_Aires", ahora, "2025-10-16 12:00"},
+		{"recordame pagar hoy a las 23", "America/Argentina/Buenos_Aires", noche, "2025-10-16 02:00"},
+		{"recordame pagar hoy a las 23", "UTC", noche, "2025-10-16 23:00"},
+		{"recordame pagar el viernes a las 8", "America/Argentina/Buenos_Aires", noche, "2025-10-17 11:00"},
+		{"recordame pagar mañana", "Asia/Tokyo", ahora, "2025-10-15 15:00"},
+		{"recordame pagar 15 de marzo a las 9", "Europe/Madrid", ahora, "2026-03-15 08:00"},
+		// El cambio de horario de Madrid es el 26 de octubre
+		{"recordame pagar 27 de octubre a las 9", "Europe/Madrid", ahora, "2025-10-27 08:00"},
+		{"recordame pagar 24 de octubre a las 9", "Europe/Madrid", ahora, "2025-10-24 07:00"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.zona+" "+c.command, func(t *testing.T) {
+			action, _, err := transformar(t, c.command, TransformOptions{
+				Location: zona(t, c.zona),
+				Clock:    FixedClock(c.now),
+			})
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if action.Date.Location() != time.UTC {
+				t.Errorf("la fecha está en %s, se esperaba UTC", action.Date.Location())
+			}
+			if got := fecha(action.Date); got != c.fecha {
+				t.Errorf("fecha = %s, se esperaba %s", got, c.fecha)
+			}
+		})
+	}
+}
@@ -5,13 +5,33 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/RodrigoGonzalez78/go_analyzer/analyzer"
 	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
-	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
-	"github.com/RodrigoGonzalez78/go_analyzer/internal/parser"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
 )
 
+// reloj da la hora de referencia de las fechas relativas. ANALYZER_NOW
+// (RFC 3339) lo fija para reproducir un análisis.
+var reloj = analyzer.SystemClock
+
 func main() {
+	if path := os.Getenv("VOCABULARY_FILE"); path != "" {
+		if err := vocab.LoadFile(path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if valor := os.Getenv("ANALYZER_NOW"); valor != "" {
+		now, err := time.Parse(time.RFC3339, valor)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ANALYZER_NOW inválido:", err)
+			os.Exit(1)
+		}
+		reloj = analyzer.FixedClock(now)
+	}
+
 	fmt.Println("Analizador de comandos de agenda en español")
 	fmt.Println("Ingresa un comando (o 'salir' para terminar):")
 
@@ -34,22 +54,54 @@ func main() {
 	}
 }
 
-// analizarComando procesa un comando y devuelve un resultado legible
+// analizarComando procesa una entrada con uno o varios comandos y devuelve un
+// resultado legible
 func analizarComando(input string) string {
-	l := lexer.New(input)
-	p := parser.New(l)
-	
-	comando, err := p.Parse()
-	if err != nil {
-		return fmt.Sprintf("Error al analizar el comando: %s", err)
+	// Usamos el mismo analizador que la API
+	results := analyzer.AnalyzeBatch(input, analyzer.Options{})
+	if len(results) == 1 {
+		return analizarResultado(results[0])
 	}
-	
-	if len(p.Errors()) > 0 {
-		return fmt.Sprintf("Errores encontrados: %s", strings.Join(p.Errors(), ", "))
+	var sb strings.Builder
+	for i, result := range results {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("Comando %d: %s\n", i+1, result.Text))
+		sb.WriteString(analizarResultado(result))
+	}
+	return sb.String()
+}
+
+// analizarResultado describe el resultado del análisis de un comando
+func analizarResultado(result analyzer.Result) string {
+	comando, errs := result.Comando, result.Errors
+	if len(errs) == 1 {
+		return fmt.Sprintf("Error al analizar el comando: %s", errs[0])
+	}
+	if len(errs) > 1 {
+		mensajes := make([]string, len(errs))
+		for i, err := range errs {
+			mensajes[i] = err.Error()
+		}
+		return fmt.Sprintf("Errores encontrados: %s", strings.Join(mensajes, ", "))
 	}
 	
 	// Formateamos el resultado de manera legible
-	return formatearResultado(comando)
+	resultado := formatearResultado(comando)
+
+	now := reloj.Now()
+	action, _, err := analyzer.TransformToActionWithOptions(comando, "", analyzer.TransformOptions{Clock: reloj, Location: now.Location()})
+	if err == nil {
+		resultado += fmt.Sprintf("- Fecha resuelta: %s\n", action.Date.In(now.Location()).Format(time.RFC3339))
+		if action.EndDate != nil {
+			resultado += fmt.Sprintf("- Fin resuelto: %s\n", action.EndDate.In(now.Location()).Format(time.RFC3339))
+		}
+		if action.Recurrence != "" {
+			resultado += fmt.Sprintf("- RRULE: %s\n", action.Recurrence)
+		}
+	}
+	return resultado
 }
 
 // formatearResultado convierte el AST en un formato legible
@@ -68,9 +120,23 @@ func formatearResultado(comando *ast.Comando) string {
 		if detalle.TipoEvento != "" {
 			sb.WriteString(fmt.Sprintf("- Tipo de evento: %s\n", detalle.TipoEvento))
 		}
-		if detalle.Nombre != "" {
+		if len(detalle.Participantes) > 0 {
+			sb.WriteString(fmt.Sprintf("- Con: %s\n", strings.Join(detalle.Participantes, ", ")))
+		} else if detalle.Nombre != "" {
 			sb.WriteString(fmt.Sprintf("- Con: %s\n", strings.TrimSpace(detalle.Nombre)))
 		}
+		if detalle.Lugar != "" {
+			sb.WriteString(fmt.Sprintf("- Lugar: %s\n", detalle.Lugar))
+		}
+		if detalle.Tema != "" {
+			sb.WriteString(fmt.Sprintf("- Tema: %s\n", detalle.Tema))
+		}
+		if detalle.Prioridad != "" {
+			sb.WriteString(fmt.Sprintf("- Prioridad: %s\n", detalle.Prioridad))
+		}
+		if len(detalle.Etiquetas) > 0 {
+			sb.WriteString(fmt.Sprintf("- Etiquetas: #%s\n", strings.Join(detalle.Etiquetas, ", #")))
+		}
 		if detalle.Texto != "" {
 			sb.WriteString(fmt.Sprintf("- Detalle: %s\n", strings.TrimSpace(detalle.Texto)))
 		}
@@ -81,14 +147,17 @@ func formatearResultado(comando *ast.Comando) string {
 		if tiempo.Fecha != nil {
 			fecha := tiempo.Fecha
 			switch fecha.Tipo {
-			case "relativa":
+			case "relativa", "desplazamiento", "fin":
 				sb.WriteString(fmt.Sprintf("- Fecha: %s\n", fecha.Valor))
 			case "diasemana":
 				sb.WriteString(fmt.Sprintf("- Día: %s\n", fecha.Valor))
 			case "especifica":
-				fechaStr := fmt.Sprintf("%d de %s", fecha.Numero, fecha.Mes)
-				if fecha.Anio != 0 {
-					fechaStr += fmt.Sprintf(" de %d", fecha.Anio)
+				fechaStr := fecha.String()
+				if fecha.Mes != "" {
+					fechaStr = fmt.Sprintf("%d de %s", fecha.Numero, fecha.Mes)
+					if fecha.Anio != 0 {
+						fechaStr += fmt.Sprintf(" de %d", fecha.Anio)
+					}
 				}
 				sb.WriteString(fmt.Sprintf("- Fecha: %s\n", fechaStr))
 			}
@@ -96,17 +165,26 @@ func formatearResultado(comando *ast.Comando) string {
 		
 		if tiempo.Hora != nil {
 			hora := tiempo.Hora
-			horaStr := fmt.Sprintf("%d", hora.Hora)
-			if hora.Minutos != 0 {
-				horaStr += fmt.Sprintf(":%02d", hora.Minutos)
-			}
-			if hora.Periodo != "" {
-				horaStr += fmt.Sprintf(" %s", hora.Periodo)
+			horaStr := hora.String()
+			if h, m, err := analyzer.HoraCanonica(comando.Locale, hora); err == nil {
+				horaStr += fmt.Sprintf(" (%02d:%02d)", h, m)
 			}
 			sb.WriteString(fmt.Sprintf("- Hora: %s\n", horaStr))
 		}
 		
-		if tiempo.Fecha == nil && tiempo.Hora == nil {
+		if tiempo.HoraFin != nil {
+			sb.WriteString(fmt.Sprintf("- Hasta: %s\n", tiempo.HoraFin))
+		}
+		
+		if tiempo.Duracion != nil {
+			sb.WriteString(fmt.Sprintf("- Duración: %s (%d min)\n", tiempo.Duracion, tiempo.Duracion.Minutos))
+		}
+		
+		if tiempo.Recurrencia != nil {
+			sb.WriteString(fmt.Sprintf("- Se repite: %s\n", tiempo.Recurrencia))
+		}
+		
+		if tiempo.Fecha == nil && tiempo.Hora == nil && tiempo.Duracion == nil && tiempo.Recurrencia == nil {
 			sb.WriteString("- Sin tiempo especificado\n")
 		}
 	}
@@ -2,31 +2,92 @@ package db
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/RodrigoGonzalez78/go_analyzer/models"
+	"gorm.io/gorm"
 )
 
 func CreateAction(action models.Action) error {
-	if err := database.Create(&action).Error; err != nil {
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := guardarEtiquetas(tx, &action); err != nil {
+			return err
+		}
+		return tx.Create(&action).Error
+	})
+	if err != nil {
 		return fmt.Errorf("error al crear la accion: %v", err)
 	}
 	return nil
 }
 
+// CreateActions crea todas las acciones en una sola transacción: si alguna
+// falla no se crea ninguna. Los IDs asignados quedan en el slice.
+func CreateActions(actions []models.Action) error {
+	err := database.Transaction(func(tx *gorm.DB) error {
+		for i := range actions {
+			if err := guardarEtiquetas(tx, &actions[i]); err != nil {
+				return err
+			}
+		}
+		return tx.Create(&actions).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error al crear las acciones: %v", err)
+	}
+	return nil
+}
+
+// guardarEtiquetas reemplaza las etiquetas de la acción por las ya guardadas
+// con el mismo nombre y crea las que falten, así cada nombre existe una sola
+// vez
+func guardarEtiquetas(tx *gorm.DB, action *models.Action) error {
+	for i := range action.Tags {
+		if err := tx.Where(models.Tag{Name: action.Tags[i].Name}).FirstOrCreate(&action.Tags[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ActionFilter restringe las acciones listadas; los campos vacíos no filtran
+type ActionFilter struct {
+	Tag      string // nombre de la etiqueta, sin "#"
+	Priority string // nivel de prioridad
+}
+
+// filtrar aplica el filtro a la consulta de acciones
+func filtrar(filtro ActionFilter) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if filtro.Tag != "" {
+			tx = tx.Where("id IN (?)", database.Table("action_tags").
+				Select("action_tags.action_id").
+				Joins("JOIN tags ON tags.id = action_tags.tag_id").
+				Where("tags.name = ?", filtro.Tag))
+		}
+		if filtro.Priority != "" {
+			tx = tx.Where("priority = ?", filtro.Priority)
+		}
+		return tx
+	}
+}
+
 func GetActionByID(id uint) (*models.Action, error) {
 	var action models.Action
-	err := database.First(&action, id).Error
+	err := database.Preload("Tags").First(&action, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &action, nil
 }
 
-func GetUserActionsPaginated(userName string, page int, pageSize int) ([]models.Action, error) {
+func GetUserActionsPaginated(userName string, page int, pageSize int, filtro ActionFilter) ([]models.Action, error) {
 	var actions []models.Action
 	offset := (page - 1) * pageSize
 
 	err := database.
+		Preload("Tags").
+		Scopes(filtrar(filtro)).
 		Where("user_name = ?", userName).
 		Limit(pageSize).
 		Offset(offset).
@@ -38,7 +99,29 @@ func GetUserActionsPaginated(userName string, page int, pageSize int) ([]models.
 	return actions, nil
 }
 
+// GetUserActionsBetween devuelve las acciones del usuario que pueden tener
+// ocurrencias entre desde (inclusive) y hasta (exclusivo): las que caen en el
+// rango y las recurrentes que empiezan antes de hasta
+func GetUserActionsBetween(userName string, desde, hasta time.Time, filtro ActionFilter) ([]models.Action, error) {
+	var actions []models.Action
+
+	err := database.
+		Preload("Tags").
+		Scopes(filtrar(filtro)).
+		Where("user_name = ?", userName).
+		Where("(recurrence = '' AND date >= ? AND date < ?) OR (recurrence <> '' AND date < ?)",
+			desde.UTC(), hasta.UTC(), hasta.UTC()).
+		Order("date").
+		Find(&actions).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
 func DeleteActionByID(id uint) error {
-	result := database.Delete(&models.Action{}, id)
+	// Borramos también la relación con las etiquetas
+	result := database.Select("Tags").Delete(&models.Action{ID: id})
 	return result.Error
 }
@@ -40,3 +40,12 @@ func GetUserByUserName(userName string) (*models.User, error) {
 
 	return &user, nil
 }
+
+// UpdateUserProfile actualiza las preferencias del usuario indicadas en campos
+func UpdateUserProfile(userName string, campos map[string]interface{}) error {
+	err := database.Model(&models.User{}).Where("user_name = ?", userName).Updates(campos).Error
+	if err != nil {
+		return fmt.Errorf("error al actualizar el perfil: %v", err)
+	}
+	return nil
+}
@@ -7,7 +7,7 @@ require (
 	gorm.io/driver/sqlite v1.6.0
 )
 
-require golang.org/x/text v0.26.0 // indirect
+require golang.org/x/text v0.26.0
 
 require (
 	github.com/golang-jwt/jwt/v5 v5.2.2
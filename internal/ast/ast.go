@@ -1,5 +1,7 @@
 package ast
 
+import "fmt"
+
 // Node representa un nodo genérico en el árbol de sintaxis abstracta
 type Node interface {
 	TokenLiteral() string
@@ -22,6 +24,7 @@ type Comando struct {
 	Verbo   Expression
 	Detalle Expression
 	Tiempo  Expression
+	Locale  string // idioma del vocabulario con que se analizó ("es", "en")
 }
 
 func (c *Comando) TokenLiteral() string { return "comando" }
@@ -29,6 +32,7 @@ func (c *Comando) TokenLiteral() string { return "comando" }
 // Verbo representa el verbo de acción (agendá, recordame, etc.)
 type Verbo struct {
 	Value string
+	Tipo  string // tipo de acción que crea el verbo ("evento", "tarea", ...)
 }
 
 func (v *Verbo) expressionNode()       {}
@@ -38,16 +42,26 @@ func (v *Verbo) TokenLiteral() string  { return v.Value }
 type DetalleEvento struct {
 	TipoEvento string // puede ser vacío
 	Nombre     string // en caso de evento con persona
-	Texto      string // descripción general
+	Texto      string // descripción general, sin los participantes, el lugar ni el tema
+	Palabras   []string // todas las palabras del detalle, en orden, sin la prioridad ni las etiquetas
+	Participantes []string // "con Juan y Ana"
+	Lugar      string // "en la oficina"
+	Tema       string // "sobre el presupuesto"
+	Prioridad  string // nivel de prioridad ("prioridad alta" → "alta")
+	Etiquetas  []string // "#trabajo", sin "#" y en minúscula
 }
 
 func (d *DetalleEvento) expressionNode()      {}
 func (d *DetalleEvento) TokenLiteral() string { return d.Texto }
 
-// Tiempo representa la información temporal (fecha y/u hora)
+// Tiempo representa la información temporal (fecha y/u hora, y si la
+// acción se repite)
 type Tiempo struct {
-	Fecha *Fecha
-	Hora  *Hora
+	Fecha       *Fecha
+	Hora        *Hora
+	HoraFin     *Hora        // fin del rango ("de 10 a 11:30"); Hora es el inicio
+	Duracion    *Duracion    // "durante 2 horas"; excluye a HoraFin
+	Recurrencia *Recurrencia // opcional; la fecha, si la hay, es desde cuándo
 }
 
 func (t *Tiempo) expressionNode()      {}
@@ -58,26 +72,116 @@ func (t *Tiempo) TokenLiteral() string {
 	if t.Hora != nil {
 		return t.Hora.TokenLiteral()
 	}
+	if t.Recurrencia != nil {
+		return t.Recurrencia.TokenLiteral()
+	}
 	return ""
 }
 
 // Fecha representa una fecha (hoy, mañana, viernes, 10 de mayo, etc.)
 type Fecha struct {
-	Tipo      string // "relativa", "diasemana", "especifica"
-	Valor     string // para fechas relativas o días de la semana
+	Tipo      string // "relativa", "diasemana", "especifica", "desplazamiento", "fin"
+	Valor     string // palabra clave de la fecha relativa o el día; texto escrito si hay modificador o desplazamiento
 	Numero    int    // para fechas específicas
-	Mes       string // para fechas específicas
+	Mes       string // para fechas específicas; vacío en "el 15"
+	MesNumero int    // mes de las fechas numéricas ("15/03" → 3)
 	Anio      int    // opcional, para fechas específicas
+
+	Dia            string          // día de la semana canónico, para "diasemana"
+	Modificador    string          // "proximo" o "este", opcional para "diasemana"
+	Desplazamiento *Desplazamiento // para "relativa" y "desplazamiento"
+	Fin            *FinDePeriodo   // para "fin"
 }
 
 func (f *Fecha) expressionNode()      {}
 func (f *Fecha) TokenLiteral() string { return f.Valor }
 
-// Hora representa una hora (a las 3, a las 15:30 pm, etc.)
+// String devuelve la fecha en su forma canónica ("hoy", "15 de marzo 2024",
+// "15/03/2024")
+func (f *Fecha) String() string {
+	if f.Tipo != "especifica" {
+		return f.Valor
+	}
+	if f.MesNumero != 0 {
+		if f.Anio != 0 {
+			return fmt.Sprintf("%02d/%02d/%d", f.Numero, f.MesNumero, f.Anio)
+		}
+		return fmt.Sprintf("%02d/%02d", f.Numero, f.MesNumero)
+	}
+	if f.Mes == "" {
+		return fmt.Sprintf("el %d", f.Numero)
+	}
+	if f.Anio != 0 {
+		return fmt.Sprintf("%d de %s %d", f.Numero, f.Mes, f.Anio)
+	}
+	return fmt.Sprintf("%d de %s", f.Numero, f.Mes)
+}
+
+// Desplazamiento es una fecha relativa a la fecha de referencia, expresada
+// como una cantidad de unidades ("en 3 días", "la semana que viene")
+type Desplazamiento struct {
+	Cantidad int
+	Unidad   string // "dia", "semana", "mes", "anio"
+}
+
+func (d *Desplazamiento) expressionNode()      {}
+func (d *Desplazamiento) TokenLiteral() string { return d.String() }
+
+// String devuelve el desplazamiento en forma canónica ("+3 dia")
+func (d *Desplazamiento) String() string {
+	return fmt.Sprintf("%+d %s", d.Cantidad, d.Unidad)
+}
+
+// Duracion es cuánto dura la acción ("durante 2 horas", "por media hora")
+type Duracion struct {
+	Valor   string // texto escrito
+	Minutos int
+}
+
+func (d *Duracion) expressionNode()      {}
+func (d *Duracion) TokenLiteral() string { return d.Valor }
+
+// String devuelve la duración tal como se escribió
+func (d *Duracion) String() string {
+	return d.Valor
+}
+
+// Recurrencia es una regla de repetición ("todos los lunes", "cada 2
+// semanas", "cada 15 del mes"). Se convierte a una RRULE (RFC 5545) al
+// transformar el comando.
+type Recurrencia struct {
+	Valor     string   // texto escrito
+	Unidad    string   // "dia", "semana", "mes", "anio"
+	Intervalo int      // cada cuántas unidades; 1 si no se indicó
+	Dias      []string // días de la semana canónicos ("todos los lunes y jueves")
+	DiaDelMes int      // para "cada 15 del mes"
+}
+
+func (r *Recurrencia) expressionNode()      {}
+func (r *Recurrencia) TokenLiteral() string { return r.Valor }
+
+// String devuelve la recurrencia tal como se escribió
+func (r *Recurrencia) String() string {
+	return r.Valor
+}
+
+// FinDePeriodo es el último día de la unidad de tiempo en curso ("fin de mes")
+type FinDePeriodo struct {
+	Unidad string // "semana", "mes", "anio"
+}
+
+func (f *FinDePeriodo) expressionNode()      {}
+func (f *FinDePeriodo) TokenLiteral() string { return "fin de " + f.Unidad }
+
+// Hora representa una hora (a las 3, a las 15:30, a las 3 pm, a las 6 menos
+// cuarto, al mediodía). Guarda la expresión tal como se escribió; la hora
+// canónica en formato 24h se calcula al transformar el comando.
 type Hora struct {
 	Hora     int
-	Minutos  int
-	Periodo  string // am, pm, hs
+	Minutos  int    // minutos que se suman ("15:30", "5 y media")
+	Menos    int    // minutos que se restan ("6 menos cuarto")
+	Periodo  string // am, pm, hs, de la tarde
+	Nombre   string // expresión de hora fija ("al mediodía"), si la hay
 }
 
 func (h *Hora) expressionNode()      {}
@@ -87,3 +191,19 @@ func (h *Hora) TokenLiteral() string {
 	}
 	return "hora"
 }
+
+// String devuelve la hora en su forma canónica ("a las 15:30", "a las 06 menos 15")
+func (h *Hora) String() string {
+	if h.Nombre != "" {
+		return h.Nombre
+	}
+
+	s := fmt.Sprintf("a las %02d:%02d", h.Hora, h.Minutos)
+	if h.Menos != 0 {
+		s = fmt.Sprintf("a las %02d menos %d", h.Hora, h.Menos)
+	}
+	if h.Periodo != "" {
+		s += " " + h.Periodo
+	}
+	return s
+}
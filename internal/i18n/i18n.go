@@ -0,0 +1,92 @@
+package i18n
+
+import "fmt"
+
+// Los mensajes se escriben en español en el código y sirven de clave para
+// buscar su traducción. Si un idioma no tiene traducción para un mensaje se
+// usa el original en español.
+var traducciones = map[string]map[string]string{
+	"en": {
+		// Parser
+		"comando vacío": "empty command",
+		"se esperaba un verbo, se encontró %s (%s)":                  "expected a verb, found %s (%s)",
+		"se esperaba un detalle de evento o texto, se encontró %s":   "expected an event detail or text, found %s",
+		"tokens inesperados: %v":                                     "unexpected tokens: %v",
+		"tokens inesperados al final: %v":                            "unexpected trailing tokens: %v",
+		"día fuera de rango: %d":                                     "day out of range: %d",
+		"mes fuera de rango: %d":                                     "month out of range: %d",
+		"año fuera de rango: %d (debe estar entre %d y %d)":          "year out of range: %d (must be between %d and %d)",
+		"la fecha %s no existe: el mes tiene %d días":                "the date %s does not exist: the month has %d days",
+		"la fecha %s no existe":                                      "the date %s does not exist",
+		"la fecha %s ya pasó":                                        "the date %s is in the past",
+		"la fecha %s ya pasó y no se puede mover":                    "the date %s is in the past and cannot be moved forward",
+		"la fecha %s ya pasó; se usó %s":                             "the date %s is in the past; %s was used instead",
+		"se esperaba un número en la fecha, se encontró %s":          "expected a number in the date, found %s",
+		"se esperaba '-' en la fecha, se encontró %s":                "expected '-' in the date, found %s",
+		"se esperaba un mes después del número, se encontró %s":      "expected a month after the number, found %s",
+		"se esperaba un día después del mes, se encontró %s":         "expected a day after the month, found %s",
+		"se esperaba un mes, se encontró %s (%s)":                    "expected a month, found %s (%s)",
+		"se esperaba un día de la semana, se encontró %s":            "expected a weekday, found %s",
+		"se esperaba una fecha, se encontró %s":                      "expected a date, found %s",
+		"se esperaba una fecha, se encontró %s (%s)":                 "expected a date, found %s (%s)",
+		"se esperaba 'a las', se encontró %s":                        "expected 'at', found %s",
+		"se esperaba hora después de 'a las', se encontró %s":        "expected an hour after 'at', found %s",
+		"hora fuera de rango: %d":                                    "hour out of range: %d",
+		"se esperaba un número para los minutos, se encontró %s":     "expected a number for the minutes, found %s",
+		"minutos deben tener 2 dígitos: '%s'":                        "minutes must have 2 digits: '%s'",
+		"minutos fuera de rango: %d":                                 "minutes out of range: %d",
+		"la hora %d no corresponde a '%s'":                           "hour %d does not match '%s'",
+		"sólo se puede repetir un día del mes, se encontró %s":       "only a day of the month can repeat, found %s",
+		"el intervalo de la repetición debe ser mayor que cero":      "the repeat interval must be greater than zero",
+		"el intervalo de la repetición no puede superar %d":          "the repeat interval cannot exceed %d",
+		"la hora de fin (%s) es igual a la de inicio":                "the end time (%s) is the same as the start time",
+		"la hora de fin (%s) debe ser posterior a la de inicio (%s)": "the end time (%s) must be after the start time (%s)",
+		"no se puede indicar la hora de fin y la duración a la vez":  "an end time and a duration cannot be given together",
+		"la duración debe ser mayor que cero":                        "the duration must be greater than zero",
+		"falta cerrar las comillas: %s":                              "missing closing quote: %s",
+		"ya se indicó la fecha (%s), se encontró otra: %s":           "a date was already given (%s), found another: %s",
+		"ya se indicó la hora (%s), se encontró otra: %s":            "a time was already given (%s), found another: %s",
+		"ya se indicó la duración (%s), se encontró otra: %s":        "a duration was already given (%s), found another: %s",
+		"ya se indicó la repetición (%s), se encontró otra: %s":      "a repetition was already given (%s), found another: %s",
+		"ya se indicó la prioridad (%s), se encontró otra: %s":       "a priority was already given (%s), found another: %s",
+		"%s parece un día mal escrito, ¿quisiste decir %s?":          "%s looks like a misspelled day, did you mean %s?",
+
+		// Transformación a acción
+		"error procesando fecha/hora: %v":       "error processing date/time: %v",
+		"fecha relativa no soportada: %s":       "unsupported relative date: %s",
+		"día de la semana inválido: %s":         "invalid weekday: %s",
+		"mes inválido: %s":                      "invalid month: %s",
+		"unidad de repetición no soportada: %s": "unsupported repeat unit: %s",
+
+		// API
+		"Error al decodificar el contenido":           "Error decoding the request body",
+		"No se envió ningún comando":                  "No command was sent",
+		"Error creando la accion":                     "Error creating the action",
+		"No se creó: otro comando tiene errores":      "Not created: another command has errors (rolled back)",
+		"Idioma no soportado: %s":                     "Unsupported language: %s",
+		"Política de fechas pasadas no soportada: %s": "Unsupported past date policy: %s",
+		"Zona horaria no soportada: %s":               "Unsupported time zone: %s",
+		"Prioridad no soportada: %s":                  "Unsupported priority: %s",
+		"Fecha inválida en %s: %s":                    "Invalid date in %s: %s",
+		"Los parámetros from y to van juntos":         "The from and to parameters must be sent together",
+		"El rango de fechas no puede superar %d días": "The date range cannot exceed %d days",
+	},
+}
+
+// T devuelve la traducción del mensaje al idioma indicado
+func T(locale, mensaje string) string {
+	if traduccion, ok := traducciones[locale][mensaje]; ok {
+		return traduccion
+	}
+	return mensaje
+}
+
+// Sprintf traduce el formato al idioma indicado y lo completa con los argumentos
+func Sprintf(locale, format string, args ...interface{}) string {
+	return fmt.Sprintf(T(locale, format), args...)
+}
+
+// Errorf es como fmt.Errorf con el formato traducido al idioma indicado
+func Errorf(locale, format string, args ...interface{}) error {
+	return fmt.Errorf(T(locale, format), args...)
+}
@@ -1,7 +1,10 @@
 package lexer
 
 import (
-	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
 )
 
 // TokenType representa el tipo de token
@@ -9,152 +12,257 @@ type TokenType string
 
 // Definición de los tipos de tokens
 const (
-	ILLEGAL   = "ILLEGAL"
-	EOF       = "EOF"
-	
+	ILLEGAL = "ILLEGAL"
+	EOF     = "EOF"
+
 	// Palabras clave
 	VERBO      = "VERBO"
 	TIPOEVENTO = "TIPOEVENTO"
 	PALABRA    = "PALABRA"
 	DE         = "DE"
 	CON        = "CON"
-	
+	SOBRE      = "SOBRE"     // "sobre", "about", antes del tema
+	TEXTO      = "TEXTO"     // texto entre comillas; Keyword guarda el texto sin ellas
+	PRIORIDAD  = "PRIORIDAD" // "urgente", "prioridad alta"
+	ETIQUETA   = "ETIQUETA"  // "#trabajo"; Keyword guarda la etiqueta sin "#"
+
 	// Fechas
 	FECHARELATIVA = "FECHARELATIVA"
 	DIASEMANA     = "DIASEMANA"
 	MES           = "MES"
-	
+	EN            = "EN"          // "en" / "dentro de", antes de un desplazamiento
+	UNIDAD        = "UNIDAD"      // "días", "semanas", "meses"
+	MODIFICADOR   = "MODIFICADOR" // "próximo", "este", "que viene"
+	FINPERIODO    = "FINPERIODO"  // "fin de mes", "fin de semana"
+
 	// Tiempo
-	ALAS      = "ALAS"
-	
+	ALAS     = "ALAS"
+	EL       = "EL"       // "el" / "on", antes de una fecha
+	HORAFIJA = "HORAFIJA" // "al mediodía", "a la medianoche"
+	FRACCION = "FRACCION" // "media", "cuarto"
+	Y        = "Y"        // "y" en "5 y media"
+	MENOS    = "MENOS"    // "menos" en "6 menos cuarto"
+
+	// Repetición y duración
+	CADA     = "CADA"     // "cada", "todos los", "every"
+	DESDE    = "DESDE"    // "desde", "from", antes de la hora de inicio
+	HASTA    = "HASTA"    // "hasta", "a", "until", antes de la hora de fin
+	DURANTE  = "DURANTE"  // "durante", "por", "for"
+	DURACION = "DURACION" // "hora", "minutos"
+
 	// Valores
-	NUMERO    = "NUMERO"
-	COLON     = "COLON"
-	PERIODO   = "PERIODO"  // am, pm, hs
+	NUMERO  = "NUMERO" // "15" o "quince"
+	COLON   = "COLON"
+	BARRA   = "BARRA"   // "/" en "15/03/2025"
+	GUION   = "GUION"   // "-" en "2025-03-15"
+	PERIODO = "PERIODO" // am, pm, hs, de la tarde
+	COMA    = "COMA"    // "," entre participantes o en la descripción
+	SIMBOLO = "SIMBOLO" // otros signos de puntuación y símbolos ("#", "(", "."), parte de la descripción
 )
 
 // Token representa un token del lenguaje
 type Token struct {
-	Type    TokenType
-	Literal string
+	Type      TokenType
+	Literal   string
+	Pos       int    // offset en bytes donde comienza el token
+	End       int    // offset en bytes donde termina el token (exclusivo)
+	Column    int    // offset en caracteres (runas) donde comienza el token
+	EndColumn int    // offset en caracteres (runas) donde termina el token (exclusivo)
+	Keyword   string // forma canónica de la palabra clave reconocida ("Mañana" → "mañana"); en los números escritos con palabras, el valor con dígitos
 }
 
-// Lexer convierte el texto de entrada en tokens
+// Options configura el reconocimiento de palabras clave
+type Options struct {
+	// StrictAccents exige que las tildes coincidan con las de la palabra clave.
+	// Por defecto se ignoran, así "agenda" o "miercoles" se reconocen igual.
+	StrictAccents bool
+
+	// Vocabulary define las palabras clave del idioma del comando. Si es nil
+	// se usa el vocabulario del idioma por defecto.
+	Vocabulary *vocab.Vocabulary
+}
+
+// Lexer convierte el texto de entrada en tokens. Recorre la entrada por
+// caracteres Unicode (runas), no por bytes, de modo que "reunión", "mañana"
+// o "sábado" se leen como una sola palabra.
 type Lexer struct {
 	input        string
-	position     int  // posición actual
-	readPosition int  // próxima posición a leer
-	ch           byte // carácter actual
+	position     int  // posición actual (offset en bytes)
+	readPosition int  // próxima posición a leer (offset en bytes)
+	column       int  // posición actual (offset en runas)
+	ch           rune // carácter actual
 	tokens       []Token
+	opts         Options
+}
+
+// estado guarda la posición del lexer para poder retroceder
+type estado struct {
+	position, readPosition, column int
+	ch                             rune
 }
 
 // New crea un nuevo Lexer
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	return NewWithOptions(input, Options{})
+}
+
+// NewWithOptions crea un nuevo Lexer con las opciones indicadas
+func NewWithOptions(input string, opts Options) *Lexer {
+	if opts.Vocabulary == nil {
+		opts.Vocabulary = vocab.Current()
+	}
+	l := &Lexer{input: input, opts: opts, column: -1}
 	l.readChar()
 	return l
 }
 
-// readChar lee el siguiente carácter
+// readChar lee el siguiente carácter (una runa completa)
 func (l *Lexer) readChar() {
 	if l.readPosition >= len(l.input) {
-		l.ch = 0 // ASCII para NUL (fin de archivo)
-	} else {
-		l.ch = l.input[l.readPosition]
+		l.ch = 0 // NUL (fin de archivo)
+		l.position = len(l.input)
+		if l.readPosition == len(l.input) {
+			l.column++
+		}
+		l.readPosition = len(l.input) + 1
+		return
 	}
+
+	r, width := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	l.ch = r
 	l.position = l.readPosition
-	l.readPosition++
+	l.readPosition += width
+	l.column++
 }
 
 // peekChar mira el siguiente carácter sin avanzar
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
 	}
-	return l.input[l.readPosition]
+	r, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	return r
+}
+
+// guardar devuelve la posición actual del lexer
+func (l *Lexer) guardar() estado {
+	return estado{position: l.position, readPosition: l.readPosition, column: l.column, ch: l.ch}
+}
+
+// restaurar vuelve el lexer a una posición guardada
+func (l *Lexer) restaurar(e estado) {
+	l.position, l.readPosition, l.column, l.ch = e.position, e.readPosition, e.column, e.ch
 }
 
 // NextToken devuelve el siguiente token
 func (l *Lexer) NextToken() Token {
 	var tok Token
-	
+
 	l.skipWhitespace()
-	
-	switch l.ch {
-	case ':':
-		tok = newToken(COLON, ":")
-	case 0:
+	inicio := l.guardar()
+
+	switch {
+	case l.ch == 0:
 		tok = newToken(EOF, "")
-	default:
-		if isLetter(l.ch) {
-			word := l.readWord()
-			
-			// Convertimos a minúsculas para comparar, pero mantenemos la palabra original
-			lowerWord := strings.ToLower(word)
-			
-			switch {
-			case isVerbo(lowerWord):
-				tok = newToken(VERBO, word)
-			case isTipoEvento(lowerWord):
-				tok = newToken(TIPOEVENTO, word)
-			case isFechaRelativa(lowerWord):
-				tok = newToken(FECHARELATIVA, word)
-			case isDiaSemana(lowerWord):
-				tok = newToken(DIASEMANA, word)
-			case isMes(lowerWord):
-				tok = newToken(MES, word)
-			case lowerWord == "con":
-				tok = newToken(CON, word)
-			case lowerWord == "de":
-				tok = newToken(DE, word)
-			case lowerWord == "a" && l.peekChar() != 0:
-				// Chequeamos si es "a las"
-				currentPos := l.position
-				l.readChar() // Avanzamos para ver si sigue " las"
-				l.skipWhitespace()
-				
-				if l.position+3 < len(l.input) && 
-				   strings.ToLower(l.input[l.position:l.position+3]) == "las" {
-					l.position += 3
-					l.readPosition = l.position + 1
-					if l.readPosition < len(l.input) {
-						l.ch = l.input[l.position]
-					} else {
-						l.ch = 0
-					}
-					tok = newToken(ALAS, "a las")
-				} else {
-					// No era "a las", retrocedemos
-					l.position = currentPos
-					l.readPosition = currentPos + 1
-					l.ch = l.input[currentPos]
-					tok = newToken(PALABRA, word)
-				}
-			default:
-				tok = newToken(PALABRA, word)
-			}
-		} else if isDigit(l.ch) {
-			number := l.readNumber()
-			// Verificamos si es seguido por un periodo
-			if isPeriodo(number) {
-				tok = newToken(PERIODO, number)
-			} else {
-				tok = newToken(NUMERO, number)
-			}
+	case l.ch == ':':
+		tok = newToken(COLON, ":")
+		l.readChar()
+	case l.ch == '/':
+		tok = newToken(BARRA, "/")
+		l.readChar()
+	case l.ch == '-':
+		tok = newToken(GUION, "-")
+		l.readChar()
+	case l.ch == ',':
+		tok = newToken(COMA, ",")
+		l.readChar()
+	case cierreComillas(l.ch) != 0:
+		tok = l.readTexto(inicio.position)
+	case l.ch == '#' && isLetter(l.peekChar()) && l.inicioPalabra():
+		tok = l.readEtiqueta(inicio.position)
+	case isLetter(l.ch):
+		// Buscamos la frase clave más larga que empieza aquí ("pasado mañana",
+		// "a las", "tengo que"); el literal conserva el texto original
+		literal, clase, keyword := l.readFrase()
+		if clase == NUMERO {
+			// Número escrito con palabras ("treinta y uno"): el literal
+			// conserva el texto y Keyword guarda el valor con dígitos
+			literal, keyword = l.readNumeroEscrito(inicio.position, keyword)
+		}
+		if clase != "" {
+			tok = newToken(clase, literal)
+			tok.Keyword = keyword
 		} else {
-			tok = newToken(ILLEGAL, string(l.ch))
+			tok = newToken(PALABRA, literal)
 		}
+	case isDigit(l.ch):
+		tok = newToken(NUMERO, l.readNumber())
+	case unicode.IsPunct(l.ch) || unicode.IsSymbol(l.ch):
+		tok = newToken(SIMBOLO, string(l.ch))
+		l.readChar()
+	default:
+		tok = newToken(ILLEGAL, string(l.ch))
+		l.readChar()
 	}
-	
-	l.readChar()
+
+	return l.withPos(tok, inicio)
+}
+
+// withPos completa la posición del token: desde inicio hasta la posición actual
+func (l *Lexer) withPos(tok Token, inicio estado) Token {
+	tok.Pos = inicio.position
+	tok.End = l.position
+	tok.Column = inicio.column
+	tok.EndColumn = l.column
+	return tok
+}
+
+// Input devuelve el texto de entrada original
+func (l *Lexer) Input() string {
+	return l.input
+}
+
+// Vocabulary devuelve el vocabulario con el que se reconocen las palabras clave
+func (l *Lexer) Vocabulary() *vocab.Vocabulary {
+	return l.opts.Vocabulary
+}
+
+// IsConjunction indica si la palabra separa dos comandos de la entrada
+// ("luego", "después"), con las mismas reglas de tildes que las palabras clave
+func (l *Lexer) IsConjunction(palabra string) bool {
+	strip := !l.opts.StrictAccents
+	for _, c := range l.opts.Vocabulary.Conjunctions {
+		if Normalize(c, strip) == Normalize(palabra, strip) {
+			return true
+		}
+	}
+	return false
+}
+
+// inicioPalabra indica si el carácter actual no está pegado a una palabra o
+// número anterior: "#trabajo" es una etiqueta, pero "tema#dos" no
+func (l *Lexer) inicioPalabra() bool {
+	anterior, _ := utf8.DecodeLastRuneInString(l.input[:l.position])
+	return l.position == 0 || !isLetter(anterior) && !isDigit(anterior)
+}
+
+// readEtiqueta lee una etiqueta desde el "#": letras, dígitos, "_" y "-"
+// ("#trabajo", "#q3_2025", "#fin-de-año")
+func (l *Lexer) readEtiqueta(position int) Token {
+	l.readChar() // Saltamos "#"
+	for isLetter(l.ch) || isDigit(l.ch) || l.ch == '_' || l.ch == '-' && isLetter(l.peekChar()) {
+		l.readChar()
+	}
+	tok := newToken(ETIQUETA, l.input[position:l.position])
+	tok.Keyword = tok.Literal[1:]
 	return tok
 }
 
-// readWord lee una palabra
+// readWord lee una palabra. Un apóstrofo entre letras es parte de la
+// palabra ("O'Higgins", "Joe's")
 func (l *Lexer) readWord() string {
 	position := l.position
-	for isLetter(l.ch) || l.ch == 'á' || l.ch == 'é' || l.ch == 'í' || 
-		l.ch == 'ó' || l.ch == 'ú' || l.ch == 'ñ' || l.ch == 'ü' {
+	for isLetter(l.ch) || isApostrofo(l.ch) && isLetter(l.peekChar()) {
 		l.readChar()
 	}
 	return l.input[position:l.position]
@@ -169,20 +277,58 @@ func (l *Lexer) readNumber() string {
 	return l.input[position:l.position]
 }
 
+// readTexto lee un texto entre comillas ("...", “...” o «...»). Si falta la
+// comilla de cierre devuelve un token ILLEGAL con el resto de la entrada.
+func (l *Lexer) readTexto(position int) Token {
+	cierre := cierreComillas(l.ch)
+	l.readChar()
+	contenido := l.position
+	for l.ch != 0 && l.ch != cierre {
+		l.readChar()
+	}
+	if l.ch == 0 {
+		return newToken(ILLEGAL, l.input[position:l.position])
+	}
+	tok := newToken(TEXTO, "")
+	tok.Keyword = l.input[contenido:l.position]
+	l.readChar()
+	tok.Literal = l.input[position:l.position]
+	return tok
+}
+
+// cierreComillas devuelve la comilla que cierra a ch, o 0 si ch no abre un texto
+func cierreComillas(ch rune) rune {
+	switch ch {
+	case '"':
+		return '"'
+	case '“':
+		return '”'
+	case '«':
+		return '»'
+	}
+	return 0
+}
+
 // skipWhitespace salta espacios en blanco
 func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+	for l.ch != 0 && unicode.IsSpace(l.ch) {
 		l.readChar()
 	}
 }
 
-// isLetter verifica si un carácter es una letra
-func isLetter(ch byte) bool {
-	return ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z')
+// isLetter verifica si un carácter es una letra. Las marcas combinables
+// (tildes escritas por separado) se consideran parte de la palabra.
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.Is(unicode.Mn, ch)
+}
+
+// isApostrofo verifica si un carácter es un apóstrofo, recto o tipográfico
+func isApostrofo(ch rune) bool {
+	return ch == '\'' || ch == '’'
 }
 
 // isDigit verifica si un carácter es un dígito
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
@@ -191,89 +337,53 @@ func newToken(tokenType TokenType, literal string) Token {
 	return Token{Type: tokenType, Literal: literal}
 }
 
-// Funciones auxiliares para identificar palabras clave
+// Tablas de palabras clave
 
-func isVerbo(word string) bool {
-	verbos := []string{"agendá", "anotá", "programá", "registrá", "organizá",
-		"agendarme", "agendar", "anotar", "programar", "registrar", "organizar",
-		"recordame", "recordarme", "tengo que", "necesito", "debo"}
-	
-	for _, v := range verbos {
-		if word == v {
-			return true
-		}
-	}
-	return false
-}
+// clasesPalabraClave define el orden en que se buscan las palabras clave
+var clasesPalabraClave = []TokenType{VERBO, TIPOEVENTO, PRIORIDAD, FECHARELATIVA, DIASEMANA, MES, HORAFIJA, FRACCION,
+	UNIDAD, MODIFICADOR, FINPERIODO}
 
-func isTipoEvento(word string) bool {
-	tiposEvento := []string{"reunión", "reunion", "cita", "encuentro", "junta", "sesión", "sesion", "entrevista"}
-	
-	for _, t := range tiposEvento {
-		if word == t {
-			return true
-		}
+// palabrasClave devuelve las palabras clave de una clase de token en el vocabulario v
+func palabrasClave(v *vocab.Vocabulary, clase TokenType) []string {
+	switch clase {
+	case VERBO:
+		return v.VerbWords()
+	case TIPOEVENTO:
+		return v.EventTypes
+	case PRIORIDAD:
+		return v.PriorityWords()
+	case FECHARELATIVA:
+		return v.RelativeDateWords()
+	case DIASEMANA:
+		return v.WeekdayWords()
+	case MES:
+		return v.MonthWords()
+	case HORAFIJA:
+		return v.FixedTimeWords()
+	case FRACCION:
+		return v.FractionWords()
+	case UNIDAD:
+		return v.UnitWords()
+	case MODIFICADOR:
+		return v.ModifierWords()
+	case FINPERIODO:
+		return v.PeriodEndWords()
 	}
-	return false
-}
-
-func isFechaRelativa(word string) bool {
-	fechasRelativas := []string{"hoy", "mañana", "manana", "pasado mañana", "pasado manana", "ayer"}
-	
-	for _, f := range fechasRelativas {
-		if word == f {
-			return true
-		}
-	}
-	return false
-}
-
-func isDiaSemana(word string) bool {
-	diasSemana := []string{"lunes", "martes", "miércoles", "miercoles", "jueves", "viernes", "sábado", "sabado", "domingo"}
-	
-	for _, d := range diasSemana {
-		if word == d {
-			return true
-		}
-	}
-	return false
-}
-
-func isMes(word string) bool {
-	meses := []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", 
-		"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
-	
-	for _, m := range meses {
-		if word == m {
-			return true
-		}
-	}
-	return false
-}
-
-func isPeriodo(word string) bool {
-	periodos := []string{"am", "pm", "hs", "horas"}
-	
-	for _, p := range periodos {
-		if word == p {
-			return true
-		}
-	}
-	return false
+	return nil
 }
 
 // Tokenize divide el texto de entrada en tokens
 func (l *Lexer) Tokenize() []Token {
 	var tokens []Token
-	
+
 	for {
 		tok := l.NextToken()
 		tokens = append(tokens, tok)
-		
+
 		if tok.Type == EOF {
 			break
 		}
 	}
-	
+
 	return tokens
 }
@@ -0,0 +1,182 @@
+package lexer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	casos := []struct {
+		palabra     string
+		sinTildes   bool
+		normalizada string
+	}{
+		{"MAÑANA", false, "mañana"},
+		{"MAÑANA", true, "manana"},
+		{"Miércoles", true, "miercoles"},
+		{"Miércoles", false, "miércoles"},
+		{"agenda\u0301", false, "agendá"}, // tilde combinada
+		{"pingüino", true, "pinguino"},
+	}
+
+	for _, c := range casos {
+		if got := Normalize(c.palabra, c.sinTildes); got != c.normalizada {
+			t.Errorf("Normalize(%q, %v) = %q, se esperaba %q", c.palabra, c.sinTildes, got, c.normalizada)
+		}
+	}
+}
+
+func TestPalabrasClaveSinTildes(t *testing.T) {
+	casos := []struct {
+		entrada  string
+		estricto bool
+		tipo     TokenType
+		keyword  string
+	}{
+		{"agenda", false, VERBO, "agendá"},
+		{"AGENDÁ", false, VERBO, "agendá"},
+		{"miercoles", false, DIASEMANA, "miércoles"},
+		{"MAÑANA", false, FECHARELATIVA, "mañana"},
+		{"Reunion", false, TIPOEVENTO, "reunión"},
+		// Con tildes estrictas sólo cambian mayúsculas y minúsculas
+		{"AGENDÁ", true, VERBO, "agendá"},
+		{"agenda", true, PALABRA, ""},
+		{"programa", true, PALABRA, ""},
+		{"PROGRAMÁ", true, VERBO, "programá"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			tok := NewWithOptions(c.entrada, Options{StrictAccents: c.estricto}).NextToken()
+			if tok.Type != c.tipo || tok.Keyword != c.keyword {
+				t.Errorf("token = %s %q, se esperaba %s %q", tok.Type, tok.Keyword, c.tipo, c.keyword)
+			}
+			if tok.Literal != c.entrada {
+				t.Errorf("literal = %q, debe conservar el texto original", tok.Literal)
+			}
+		})
+	}
+}
+
+func TestPosiciones(t *testing.T) {
+	entrada := "agendá reunión mañana a las 10:30"
+	esperados := []struct {
+		tipo              TokenType
+		literal           string
+		pos, end          int
+		column, endColumn int
+	}{
+		{VERBO, "agendá", 0, 7, 0, 6},
+		{TIPOEVENTO, "reunión", 8, 16, 7, 14},
+		{FECHARELATIVA, "mañana", 17, 24, 15, 21},
+		{ALAS, "a las", 25, 30, 22, 27},
+		{NUMERO, "10", 31, 33, 28, 30},
+		{COLON, ":", 33, 34, 30, 31},
+		{NUMERO, "30", 34, 36, 31, 33},
+		{EOF, "", 36, 36, 33, 33},
+	}
+
+	tokens := New(entrada).Tokenize()
+	if len(tokens) != len(esperados) {
+		t.Fatalf("tokens = %v, se esperaban %d", tokens, len(esperados))
+	}
+	for i, e := range esperados {
+		tok := tokens[i]
+		if tok.Type != e.tipo || tok.Literal != e.literal || tok.Pos != e.pos || tok.End != e.end ||
+			tok.Column != e.column || tok.EndColumn != e.endColumn {
+			t.Errorf("token %d = %+v, se esperaba %+v", i, tok, e)
+		}
+		if entrada[tok.Pos:tok.End] != tok.Literal {
+			t.Errorf("token %d: la entrada en [%d,%d) no es %q", i, tok.Pos, tok.End, tok.Literal)
+		}
+	}
+}
+
+func TestPalabrasUnicode(t *testing.T) {
+	casos := []struct {
+		entrada string
+		literal string
+		tipo    TokenType
+	}{
+		{"sábado", "sábado", DIASEMANA},
+		{"Zoë", "Zoë", PALABRA},
+		{"Müller", "Müller", PALABRA},
+		{"東京", "東京", PALABRA},
+		{"ñandú", "ñandú", PALABRA},
+		{"O'Higgins", "O'Higgins", PALABRA},
+		{"Joe’s", "Joe’s", PALABRA},
+	}
+
+	for _, c := range casos {
+		tokens := New(c.entrada).Tokenize()
+		if len(tokens) != 2 || tokens[0].Literal != c.literal || tokens[0].Type != c.tipo {
+			t.Errorf("%q: tokens = %v, se esperaba una palabra %s", c.entrada, tokens, c.tipo)
+		}
+	}
+}
+
+func TestPalabrasClaveCompuestas(t *testing.T) {
+	casos := []struct {
+		entrada string
+		tipos   []TokenType
+		literal string // literal del primer token
+	}{
+		{"pasado mañana", []TokenType{FECHARELATIVA}, "pasado mañana"},
+		{"Pasado  Manana", []TokenType{FECHARELATIVA}, "Pasado  Manana"},
+		{"tengo que pagar", []TokenType{VERBO, PALABRA}, "tengo que"},
+		{"a las 10", []TokenType{ALAS, NUMERO}, "a las"},
+		{"la semana que viene", []TokenType{FECHARELATIVA}, "la semana que viene"},
+		// Si la frase no se completa se leen las palabras sueltas
+		{"pasado el lunes", []TokenType{PALABRA, EL, DIASEMANA}, "pasado"},
+		{"tengo dudas", []TokenType{PALABRA, PALABRA}, "tengo"},
+		{"la semana", []TokenType{PALABRA, UNIDAD}, "la"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			tokens := New(c.entrada).Tokenize()
+			var tipos []TokenType
+			for _, tok := range tokens[:len(tokens)-1] {
+				tipos = append(tipos, tok.Type)
+			}
+			if !slices.Equal(tipos, c.tipos) {
+				t.Errorf("tipos = %v, se esperaba %v", tipos, c.tipos)
+			}
+			if tokens[0].Literal != c.literal {
+				t.Errorf("literal = %q, se esperaba %q", tokens[0].Literal, c.literal)
+			}
+		})
+	}
+}
+
+func TestNumerosEscritos(t *testing.T) {
+	casos := []struct {
+		entrada string
+		valores []string // Keyword de cada token NUMERO
+	}{
+		{"cinco", []string{"5"}},
+		{"primero", []string{"1"}},
+		{"treinta y uno", []string{"31"}},
+		{"veinticinco", []string{"25"}},
+		{"mil novecientos noventa y nueve", []string{"1999"}},
+		{"dos mil veintiséis", []string{"2026"}},
+		{"ciento cinco", []string{"105"}},
+		// Una palabra mayor que la última cifra empieza otro número
+		{"once quince", []string{"11", "15"}},
+		{"diez y cuarto", []string{"10"}},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			var valores []string
+			for _, tok := range New(c.entrada).Tokenize() {
+				if tok.Type == NUMERO {
+					valores = append(valores, tok.Keyword)
+				}
+			}
+			if !slices.Equal(valores, c.valores) {
+				t.Errorf("números = %q, se esperaba %q", valores, c.valores)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package lexer
+
+import (
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize lleva una palabra a la forma que se usa para compararla con las
+// palabras clave: composición canónica NFC (así "á" escrita como "a" + tilde
+// combinada es igual a "á") y case folding ("MAÑANA" → "mañana"). Si
+// stripDiacritics es true además quita tildes y diéresis ("miércoles" →
+// "miercoles", "mañana" → "manana"), para quienes escriben sin acentos.
+//
+// La normalización sólo se usa para comparar: los tokens conservan el texto
+// original en Literal.
+func Normalize(word string, stripDiacritics bool) string {
+	word = cases.Fold().String(norm.NFC.String(word))
+	if !stripDiacritics {
+		return word
+	}
+
+	sinMarcas := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(sinMarcas, word)
+	if err != nil {
+		return word
+	}
+	return result
+}
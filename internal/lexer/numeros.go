@@ -0,0 +1,124 @@
+package lexer
+
+import (
+	"strconv"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+)
+
+// numeroEscrito acumula el valor de un número escrito con palabras
+// ("mil novecientos noventa y nueve"). Los miles se guardan aparte para
+// poder sumar lo que sigue a "mil".
+type numeroEscrito struct {
+	miles  int
+	actual int
+}
+
+func (n numeroEscrito) valor() int {
+	return n.miles + n.actual
+}
+
+// sumar agrega una palabra al número si puede continuarlo. Una palabra se
+// suma sólo si es menor que la última cifra leída ("ciento cinco", "twenty
+// one"); así "once quince" o "diez cinco" son dos números distintos.
+func (n *numeroEscrito) sumar(num vocab.Number) bool {
+	switch {
+	case num.Multiplier && num.Value == 1000:
+		if n.miles != 0 {
+			return false
+		}
+		n.miles, n.actual = max(n.actual, 1)*1000, 0
+	case num.Multiplier:
+		if n.actual > 9 {
+			return false
+		}
+		n.actual = max(n.actual, 1) * num.Value
+	case n.actual == 0:
+		n.actual = num.Value
+	case num.Value < ultimaCifra(n.actual) && !(n.actual%100 >= 10 && n.actual%100 < 20):
+		n.actual += num.Value
+	default:
+		return false
+	}
+	return true
+}
+
+// unir indica si un conector puede unir el número con la palabra siguiente:
+// sólo decenas con unidades ("treinta y uno"). Veinte no se une porque
+// "veinticinco" se escribe junto, y "a las veinte y cinco" son las 20:05.
+func (n numeroEscrito) unir(num vocab.Number) bool {
+	decena := n.actual % 100
+	return !num.Multiplier && num.Value >= 1 && num.Value <= 9 && decena >= 30 && decena%10 == 0
+}
+
+// ultimaCifra devuelve la potencia de diez de la última cifra distinta de
+// cero: 900 → 100, 990 → 10, 5 → 1
+func ultimaCifra(n int) int {
+	cifra := 1
+	for n%(cifra*10) == 0 {
+		cifra *= 10
+	}
+	return cifra
+}
+
+// readNumeroEscrito continúa un número escrito con palabras cuya primera
+// palabra ya se leyó. Devuelve el literal completo y su valor con dígitos,
+// y deja el lexer después de la última palabra que forma parte del número.
+func (l *Lexer) readNumeroEscrito(inicio int, primera string) (literal, valor string) {
+	v := l.opts.Vocabulary
+	strip := !l.opts.StrictAccents
+	raiz := trieFrases(v, strip)
+
+	var n numeroEscrito
+	num, _ := v.NumberOf(primera)
+	n.sumar(num)
+	fin := l.guardar()
+
+	for {
+		l.skipWhitespace()
+		if !isLetter(l.ch) {
+			break
+		}
+		palabra := Normalize(l.readWord(), strip)
+
+		// "treinta y uno": el conector sólo se consume si le sigue una unidad
+		if l.esConectorNumero(palabra, strip) {
+			l.skipWhitespace()
+			num, ok := l.numeroEn(raiz, Normalize(l.readWord(), strip))
+			if !ok || !n.unir(num) {
+				break
+			}
+			n.actual += num.Value
+			fin = l.guardar()
+			continue
+		}
+
+		num, ok := l.numeroEn(raiz, palabra)
+		if !ok || !n.sumar(num) {
+			break
+		}
+		fin = l.guardar()
+	}
+
+	l.restaurar(fin)
+	return l.input[inicio:l.position], strconv.Itoa(n.valor())
+}
+
+// numeroEn busca una palabra normalizada en el trie y devuelve el número que nombra
+func (l *Lexer) numeroEn(raiz *nodoFrase, palabra string) (vocab.Number, bool) {
+	nodo := raiz.siguiente(palabra)
+	if nodo == nil || nodo.clase != NUMERO {
+		return vocab.Number{}, false
+	}
+	return l.opts.Vocabulary.NumberOf(nodo.keyword)
+}
+
+// esConectorNumero indica si la palabra normalizada une decenas y unidades
+func (l *Lexer) esConectorNumero(palabra string, strip bool) bool {
+	for _, c := range l.opts.Vocabulary.NumberJoiners {
+		if Normalize(c, strip) == palabra {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package lexer
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+)
+
+// Suggestion es una palabra clave parecida a una palabra desconocida
+type Suggestion struct {
+	Word     string    `json:"word"`
+	Type     TokenType `json:"type"`
+	Distance int       `json:"distance"`
+}
+
+// maxSuggestions es la cantidad máxima de sugerencias que se devuelven
+const maxSuggestions = 3
+
+// Suggest devuelve las palabras clave de las clases indicadas más parecidas a
+// word, ordenadas de la más a la menos parecida. La comparación ignora
+// mayúsculas y tildes, de modo que "miercoles" sugiere "miércoles" con
+// distancia 0. Las variantes sin tilde de una misma palabra se agrupan y se
+// sugiere la primera de la tabla. Usa el vocabulario del idioma por defecto.
+func Suggest(word string, clases ...TokenType) []Suggestion {
+	return SuggestIn(vocab.Current(), word, clases...)
+}
+
+// SuggestIn es como Suggest pero busca en el vocabulario v
+func SuggestIn(v *vocab.Vocabulary, word string, clases ...TokenType) []Suggestion {
+	plegada := fold(word)
+	if plegada == "" {
+		return nil
+	}
+
+	var sugerencias []Suggestion
+	vistas := map[string]bool{}
+	for _, clase := range clases {
+		for _, clave := range palabrasClave(v, clase) {
+			clavePlegada := fold(clave)
+			if vistas[clavePlegada] {
+				continue
+			}
+			vistas[clavePlegada] = true
+
+			d := distancia(plegada, clavePlegada)
+			if d <= maxDistancia(clavePlegada) {
+				sugerencias = append(sugerencias, Suggestion{Word: clave, Type: clase, Distance: d})
+			}
+		}
+	}
+
+	sort.SliceStable(sugerencias, func(i, j int) bool {
+		return sugerencias[i].Distance < sugerencias[j].Distance
+	})
+	if len(sugerencias) > maxSuggestions {
+		sugerencias = sugerencias[:maxSuggestions]
+	}
+	return sugerencias
+}
+
+// Unambiguous indica si la mejor sugerencia es la única con la menor
+// distancia, es decir, si se puede corregir automáticamente sin adivinar.
+func Unambiguous(sugerencias []Suggestion) bool {
+	if len(sugerencias) == 0 {
+		return false
+	}
+	return len(sugerencias) == 1 || sugerencias[0].Distance < sugerencias[1].Distance
+}
+
+// maxDistancia define cuántas ediciones se toleran según el largo de la palabra clave
+func maxDistancia(clave string) int {
+	switch n := utf8.RuneCountInString(clave); {
+	case n <= 3:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// fold normaliza la palabra sin tildes ni mayúsculas, para comparar sin
+// importar cómo fue escrita
+func fold(word string) string {
+	return Normalize(word, true)
+}
+
+// distancia calcula la distancia de edición entre a y b (inserciones,
+// borrados, sustituciones y transposiciones de caracteres adyacentes)
+func distancia(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			costo := 1
+			if ra[i-1] == rb[j-1] {
+				costo = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+costo)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
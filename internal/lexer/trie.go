@@ -0,0 +1,136 @@
+package lexer
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+)
+
+// nodoFrase es un nodo del trie de frases. Cada arista es una palabra
+// normalizada, de modo que una frase de varias palabras ("pasado mañana",
+// "a las", "de la tarde") es un camino desde la raíz.
+type nodoFrase struct {
+	hijos   map[string]*nodoFrase
+	clase   TokenType // clase de la frase que termina en este nodo ("" si ninguna)
+	keyword string    // forma canónica de la frase
+}
+
+// insertar agrega una frase al trie. Si la frase ya existía se conserva la
+// primera clase registrada.
+func (n *nodoFrase) insertar(palabras []string, clase TokenType, keyword string) {
+	nodo := n
+	for _, palabra := range palabras {
+		if nodo.hijos == nil {
+			nodo.hijos = map[string]*nodoFrase{}
+		}
+		hijo, ok := nodo.hijos[palabra]
+		if !ok {
+			hijo = &nodoFrase{}
+			nodo.hijos[palabra] = hijo
+		}
+		nodo = hijo
+	}
+	if nodo.clase == "" {
+		nodo.clase = clase
+		nodo.keyword = keyword
+	}
+}
+
+// siguiente devuelve el nodo al que se llega con la palabra, o nil
+func (n *nodoFrase) siguiente(palabra string) *nodoFrase {
+	if n == nil || n.hijos == nil {
+		return nil
+	}
+	return n.hijos[palabra]
+}
+
+// claveTrie identifica un trie construido para un vocabulario y un modo de normalización
+type claveTrie struct {
+	vocabulario *vocab.Vocabulary
+	strip       bool
+}
+
+var (
+	triesMu sync.Mutex
+	tries   = map[claveTrie]*nodoFrase{}
+)
+
+// trieFrases devuelve el trie con todas las palabras clave y conectores del
+// vocabulario v, normalizados según strip. Se construye una sola vez por
+// vocabulario y modo.
+func trieFrases(v *vocab.Vocabulary, strip bool) *nodoFrase {
+	clave := claveTrie{v, strip}
+
+	triesMu.Lock()
+	defer triesMu.Unlock()
+
+	if raiz, ok := tries[clave]; ok {
+		return raiz
+	}
+
+	raiz := &nodoFrase{}
+	for _, clase := range clasesPalabraClave {
+		for _, palabra := range palabrasClave(v, clase) {
+			raiz.insertar(strings.Fields(Normalize(palabra, strip)), clase, palabra)
+		}
+	}
+	for _, c := range v.Connectors {
+		raiz.insertar(strings.Fields(Normalize(c.Word, strip)), TokenType(c.Token), c.Word)
+	}
+	for _, periodo := range v.PeriodWords() {
+		raiz.insertar(strings.Fields(Normalize(periodo, strip)), PERIODO, periodo)
+	}
+	// Después de los periodos, así "horas" sigue siendo el periodo de "a las
+	// 10 horas"; el parser también lo acepta como unidad de duración
+	for _, unidad := range v.DurationUnitWords() {
+		raiz.insertar(strings.Fields(Normalize(unidad, strip)), DURACION, unidad)
+	}
+	for _, numero := range v.NumberWords() {
+		raiz.insertar(strings.Fields(Normalize(numero, strip)), NUMERO, numero)
+	}
+
+	// Los vocabularios reemplazados con vocab.Set no se vuelven a usar
+	for k := range tries {
+		if vocab.Get(k.vocabulario.Locale) != k.vocabulario {
+			delete(tries, k)
+		}
+	}
+	tries[clave] = raiz
+	return raiz
+}
+
+// readFrase lee la frase más larga del trie que empieza en la palabra actual.
+// Si ninguna frase coincide lee sólo la palabra y devuelve clase vacía; si
+// coincide, el lexer queda justo después de la última palabra de la frase.
+func (l *Lexer) readFrase() (literal string, clase TokenType, keyword string) {
+	strip := !l.opts.StrictAccents
+	inicio := l.position
+
+	word := l.readWord()
+	literal = word
+	fin := l.guardar()
+
+	nodo := trieFrases(l.opts.Vocabulary, strip).siguiente(Normalize(word, strip))
+	if nodo != nil && nodo.clase != "" {
+		clase, keyword = nodo.clase, nodo.keyword
+	}
+
+	// Seguimos mientras las palabras siguientes continúen alguna frase y
+	// recordamos la última posición donde terminó una frase completa
+	for nodo != nil {
+		l.skipWhitespace()
+		if !isLetter(l.ch) {
+			break
+		}
+		nodo = nodo.siguiente(Normalize(l.readWord(), strip))
+		if nodo != nil && nodo.clase != "" {
+			clase, keyword = nodo.clase, nodo.keyword
+			literal = l.input[inicio:l.position]
+			fin = l.guardar()
+		}
+	}
+
+	l.restaurar(fin)
+	return literal, clase, keyword
+}
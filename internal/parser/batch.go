@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+)
+
+// Sentencia es uno de los comandos de una entrada con varios, con lo que se
+// encontró al analizarlo
+type Sentencia struct {
+	Comando        *ast.Comando // nil si el comando tiene errores
+	Inicio, Fin    int          // offsets en bytes del comando dentro de la entrada
+	Errors         []*AnalyzerError
+	Corrections    []Correction
+	Normalizations []Normalization
+	Warnings       []*AnalyzerError
+}
+
+// ParseSentencias analiza la regla
+//
+//	ENTRADA   → COMANDO { SEPARADOR COMANDO }
+//	SEPARADOR → ( "y" | "," | ";" | "." | CONJUNCION | SALTO_DE_LINEA ) { ... }
+//
+// Un separador sólo corta la entrada si le sigue otro verbo, con o sin tiempo
+// antes: "agendá dentista el lunes a las 9 y recordame comprar pan el martes"
+// son dos comandos, pero "anotá comprar pan y leche" es uno. Cada sentencia
+// tiene sus propios errores, correcciones, ajustes y avisos, y un error en un
+// comando no impide analizar los demás.
+func (p *Parser) ParseSentencias() []Sentencia {
+	var sentencias []Sentencia
+	for {
+		inicio := p.guardar()
+		comando, _ := p.ParseComando()
+		sentencias = append(sentencias, Sentencia{
+			Comando:        comando,
+			Inicio:         inicio.cur.Pos,
+			Fin:            p.anterior().End,
+			Errors:         p.errors[inicio.errores:len(p.errors):len(p.errors)],
+			Corrections:    p.corrections[inicio.correcciones:len(p.corrections):len(p.corrections)],
+			Normalizations: p.normalizations[inicio.normalizaciones:len(p.normalizations):len(p.normalizations)],
+			Warnings:       p.warnings[inicio.avisos:len(p.warnings):len(p.warnings)],
+		})
+
+		for p.esSeparador(p.curToken) {
+			p.nextToken()
+		}
+		if p.curToken.Type == lexer.EOF {
+			return sentencias
+		}
+	}
+}
+
+// estado es la posición del parser y lo registrado hasta ella, para volver
+// atrás después de mirar hacia adelante
+type estado struct {
+	position                               int
+	cur, peek                              lexer.Token
+	errores, correcciones, normalizaciones int
+	avisos                                 int
+}
+
+// guardar devuelve el estado actual del parser
+func (p *Parser) guardar() estado {
+	return estado{
+		position:        p.position,
+		cur:             p.curToken,
+		peek:            p.peekToken,
+		errores:         len(p.errors),
+		correcciones:    len(p.corrections),
+		normalizaciones: len(p.normalizations),
+		avisos:          len(p.warnings),
+	}
+}
+
+// restaurar vuelve al estado guardado y descarta lo registrado después
+func (p *Parser) restaurar(e estado) {
+	p.position, p.curToken, p.peekToken = e.position, e.cur, e.peek
+	p.errors = p.errors[:e.errores]
+	p.corrections = p.corrections[:e.correcciones]
+	p.normalizations = p.normalizations[:e.normalizaciones]
+	p.warnings = p.warnings[:e.avisos]
+}
+
+// esSeparador indica si el token puede separar dos comandos: "y", una coma,
+// un punto y coma, un punto o una conjunción del vocabulario ("luego")
+func (p *Parser) esSeparador(tok lexer.Token) bool {
+	switch tok.Type {
+	case lexer.Y, lexer.COMA:
+		return true
+	case lexer.SIMBOLO:
+		return tok.Literal == ";" || tok.Literal == "."
+	case lexer.PALABRA:
+		return p.l.IsConjunction(tok.Literal)
+	}
+	return false
+}
+
+// saltoDeLinea indica si el token está en otra línea que el anterior
+func (p *Parser) saltoDeLinea(tok lexer.Token) bool {
+	antes := p.l.Input()[:tok.Pos]
+	return strings.ContainsRune(antes[len(strings.TrimRightFunc(antes, unicode.IsSpace)):], '\n')
+}
+
+// esFinComando indica si en el token actual termina el comando y comienza
+// otro: uno o más separadores o un salto de línea seguidos de un verbo, con o
+// sin tiempo, prioridad o etiquetas antes ("y mañana recordame comprar pan").
+// Un verbo sin nada que lo separe del comando anterior sigue siendo un token
+// inesperado.
+func (p *Parser) esFinComando() bool {
+	if p.curToken.Type == lexer.EOF {
+		return false
+	}
+	e := p.guardar()
+	defer p.restaurar(e)
+
+	separado := p.saltoDeLinea(p.curToken)
+	for p.esSeparador(p.curToken) {
+		separado = true
+		p.nextToken()
+	}
+	if !separado {
+		return false
+	}
+
+	// El tiempo que se lee acá se descarta; es uno solo para que un conflicto
+	// ("y mañana el lunes") pueda describir la parte anterior. Sus errores los
+	// descarta restaurar.
+	tiempo := &ast.Tiempo{}
+	var vistas partesTiempo
+	for p.esInicioTiempo() || p.esMarca(p.curToken) {
+		if p.esMarca(p.curToken) {
+			p.nextToken()
+			continue
+		}
+		p.parseParteTiempo(tiempo, &vistas)
+	}
+	return p.curToken.Type == lexer.VERBO
+}
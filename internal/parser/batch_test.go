@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSentencias(t *testing.T) {
+	casos := []struct {
+		entrada    string
+		locale     string
+		sentencias []string // texto de cada comando
+		errores    []string // código del primer error de cada comando, vacío si no tiene
+	}{
+		{"agendá dentista el lunes a las 9 y recordame comprar pan el martes", "es",
+			[]string{"agendá dentista el lunes a las 9", "recordame comprar pan el martes"}, []string{"", ""}},
+		{"anotá comprar pan y leche", "es", []string{"anotá comprar pan y leche"}, []string{""}},
+		{"agendá dentista y mañana recordame pagar la luz", "es",
+			[]string{"agendá dentista", "mañana recordame pagar la luz"}, []string{"", ""}},
+		{"agendá dentista, luego recordame pan; anotá ideas", "es",
+			[]string{"agendá dentista", "recordame pan", "anotá ideas"}, []string{"", "", ""}},
+		{"agendá dentista\nrecordame pan", "es", []string{"agendá dentista", "recordame pan"}, []string{"", ""}},
+		{"agendá reunión a las 25 y recordame pan", "es",
+			[]string{"agendá reunión a las 25", "recordame pan"}, []string{CodigoHoraInvalida, ""}},
+		// Dos fechas antes del verbo siguiente: el conflicto es del segundo comando
+		{"agendá reunión y mañana el lunes recordame pan", "es",
+			[]string{"agendá reunión", "mañana el lunes recordame pan"}, []string{"", CodigoConflictoTiempo}},
+		{"schedule dentist on monday at 9 and remind me to buy bread on tuesday", "en",
+			[]string{"schedule dentist on monday at 9", "remind me to buy bread on tuesday"}, []string{"", ""}},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			sentencias := nuevoParser(c.entrada, c.locale, Options{}).ParseSentencias()
+			var textos, errores []string
+			for _, s := range sentencias {
+				textos = append(textos, c.entrada[s.Inicio:s.Fin])
+				codigo := ""
+				if len(s.Errors) > 0 {
+					codigo = s.Errors[0].Code
+				}
+				if (s.Comando == nil) != (codigo != "") {
+					t.Errorf("%q: comando = %v con errores %v", c.entrada[s.Inicio:s.Fin], s.Comando, s.Errors)
+				}
+				errores = append(errores, codigo)
+			}
+			if strings.Join(textos, "|") != strings.Join(c.sentencias, "|") {
+				t.Errorf("sentencias = %q, se esperaba %q", textos, c.sentencias)
+			}
+			if strings.Join(errores, "|") != strings.Join(c.errores, "|") {
+				t.Errorf("errores = %q, se esperaba %q", errores, c.errores)
+			}
+		})
+	}
+}
+
+func TestFinComandoSinPanico(t *testing.T) {
+	// Mirar hacia adelante dos fechas seguidas no debe fallar ni dejar errores
+	casos := []struct {
+		entrada string
+		errores int
+	}{
+		{"agendá reunión y mañana el lunes", 1},
+		{"agendá reunión y a las 9 a las 10", 1},
+		{"agendá reunión y durante 2 horas durante 1 hora", 1},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, errs := parsear(c.entrada, Options{})
+			if len(errs) != c.errores {
+				t.Errorf("errores = %v, se esperaban %d", errs, c.errores)
+			}
+		})
+	}
+}
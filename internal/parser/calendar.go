@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"time"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+)
+
+// Rango de años aceptado en las fechas específicas
+const (
+	anioMinimo = 1900
+	anioMaximo = 2100
+)
+
+// anioValido indica si el año está dentro del rango aceptado
+func anioValido(anio int) bool {
+	return anio >= anioMinimo && anio <= anioMaximo
+}
+
+// anioBisiesto se usa para validar las fechas sin año: "29 de febrero"
+// existe, aunque no todos los años
+const anioBisiesto = 2000
+
+// Normalization describe una fecha inexistente que el modo permisivo ajustó
+// al día que le corresponde en el calendario ("31 de febrero 2025" → "3 de marzo 2025")
+type Normalization struct {
+	Original   string `json:"original"`
+	Normalized string `json:"normalized"`
+	Reason     string `json:"reason"`
+	Start      int    `json:"start"` // offset en bytes (inclusive)
+	End        int    `json:"end"`   // offset en bytes (exclusivo)
+}
+
+// Normalizations devuelve las fechas ajustadas en modo permisivo
+func (p *Parser) Normalizations() []Normalization {
+	return p.normalizations
+}
+
+// validarFecha comprueba que una fecha específica exista en el calendario:
+// el día dentro del mes, con los años bisiestos. El error abarca la fecha
+// completa, entre desde y hasta. En modo permisivo la fecha se ajusta como lo
+// haría el calendario ("0 de enero" → "31 de diciembre") y se registra la
+// normalización; un día sin mes sigue siendo un error. Un año fuera de rango
+// ya lo informó parseAnio y la fecha no se valida de nuevo.
+func (p *Parser) validarFecha(fecha *ast.Fecha, desde, hasta lexer.Token) {
+	if fecha.Anio != 0 && !anioValido(fecha.Anio) {
+		return
+	}
+
+	mes := fecha.MesNumero
+	if fecha.Mes != "" {
+		mes, _ = p.l.Vocabulary().MonthNumber(fecha.Mes)
+	}
+	anio := fecha.Anio
+	if anio == 0 {
+		anio = anioBisiesto
+	}
+
+	var formato string
+	var args []interface{}
+	switch {
+	case mes < 1 || mes > 12:
+		// "el 15": el mes se elige al transformar, sólo se valida el día. Un
+		// mes numérico fuera de rango ya lo informó parseMesNumero.
+		if fecha.MesNumero == 0 && (fecha.Numero < 1 || fecha.Numero > 31) {
+			p.addError(p.errorEntre(desde, hasta, CodigoFechaInvalida, nil, "día fuera de rango: %d", fecha.Numero))
+		}
+		return
+	case fecha.Numero < 1 || fecha.Numero > 31:
+		formato, args = "día fuera de rango: %d", []interface{}{fecha.Numero}
+	case fecha.Numero > diasDelMes(anio, mes):
+		formato, args = "la fecha %s no existe: el mes tiene %d días", []interface{}{fecha.String(), diasDelMes(anio, mes)}
+	default:
+		return
+	}
+
+	if !p.opts.Lenient {
+		p.addError(p.errorEntre(desde, hasta, CodigoFechaInvalida, nil, formato, args...))
+		return
+	}
+	p.normalizar(fecha, mes, anio, i18n.Sprintf(p.locale(), formato, args...), desde, hasta)
+}
+
+// normalizar ajusta la fecha al día que le corresponde en el calendario y
+// registra el cambio con su motivo
+func (p *Parser) normalizar(fecha *ast.Fecha, mes, anio int, motivo string, desde, hasta lexer.Token) {
+	original := fecha.String()
+
+	t := time.Date(anio, time.Month(mes), fecha.Numero, 0, 0, 0, 0, time.UTC)
+	fecha.Numero = t.Day()
+	if fecha.Anio != 0 {
+		fecha.Anio = t.Year()
+	}
+	if fecha.Mes != "" {
+		fecha.Mes, _ = p.l.Vocabulary().MonthWord(int(t.Month()))
+	} else {
+		fecha.MesNumero = int(t.Month())
+	}
+
+	p.normalizations = append(p.normalizations, Normalization{
+		Original:   original,
+		Normalized: fecha.String(),
+		Reason:     motivo,
+		Start:      desde.Pos,
+		End:        hasta.End,
+	})
+}
+
+// diasDelMes devuelve la cantidad de días del mes en el año indicado
+func diasDelMes(anio, mes int) int {
+	return time.Date(anio, time.Month(mes)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
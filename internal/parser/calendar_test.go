@@ -0,0 +1,87 @@
+package parser
+
+import "testing"
+
+func TestValidarFecha(t *testing.T) {
+	casos := []struct {
+		entrada string
+		error   string // token del error; vacío si la fecha existe
+	}{
+		{"agendá algo 29 de febrero 2028", ""},
+		{"agendá algo 29 de febrero", ""},
+		{"agendá algo 31/12/2025", ""},
+		{"agendá algo 31 de febrero 2025", "31 de febrero 2025"},
+		{"agendá algo 29 de febrero 2025", "29 de febrero 2025"},
+		{"agendá algo 0 de enero", "0 de enero"},
+		{"agendá algo 31 de abril", "31 de abril"},
+		{"agendá algo 30/02", "30/02"},
+		{"agendá algo 2025-02-30", "2025-02-30"},
+		{"agendá algo el 32", "32"},
+		{"agendá algo 15/13", "13"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, errs := parsear(c.entrada, Options{})
+			if c.error == "" {
+				if len(errs) > 0 {
+					t.Fatalf("errores inesperados: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("errores = %v, se esperaba uno", errs)
+			}
+			e := errs[0]
+			if e.Code != CodigoFechaInvalida || e.Token != c.error {
+				t.Errorf("error = %s %q, se esperaba %s %q", e.Code, e.Token, CodigoFechaInvalida, c.error)
+			}
+			if c.entrada[e.Start:e.End] != e.Token {
+				t.Errorf("el error en [%d,%d) no ubica a %q", e.Start, e.End, e.Token)
+			}
+		})
+	}
+}
+
+func TestFechaPermisiva(t *testing.T) {
+	casos := []struct {
+		entrada     string
+		fecha       string
+		normalizada string
+	}{
+		{"agendá algo 31 de febrero 2025", "3 de marzo 2025", "31 de febrero 2025→3 de marzo 2025"},
+		{"agendá algo 0 de enero 2026", "31 de diciembre 2025", "0 de enero 2026→31 de diciembre 2025"},
+		{"agendá algo 31/04", "01/05", "31/04→01/05"},
+		{"agendá algo 15 de marzo", "15 de marzo", ""},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			p := nuevoParser(c.entrada, "es", Options{Lenient: true})
+			comando, err := p.Parse()
+			if err != nil {
+				t.Fatalf("error inesperado: %v", p.Errors())
+			}
+			_, _, tiempo := partes(t, comando)
+			if got := textoFecha(tiempo.Fecha); got != c.fecha {
+				t.Errorf("fecha = %q, se esperaba %q", got, c.fecha)
+			}
+			normalizada := ""
+			for _, n := range p.Normalizations() {
+				normalizada = n.Original + "→" + n.Normalized
+				if n.Reason == "" || c.entrada[n.Start:n.End] == "" {
+					t.Errorf("normalización sin motivo o sin ubicación: %+v", n)
+				}
+			}
+			if normalizada != c.normalizada {
+				t.Errorf("normalización = %q, se esperaba %q", normalizada, c.normalizada)
+			}
+		})
+	}
+
+	// Un año fuera de rango sigue siendo un error en modo permisivo
+	p := nuevoParser("agendá algo 15 de marzo 99", "es", Options{Lenient: true})
+	if _, err := p.Parse(); err == nil {
+		t.Error("se esperaba un error por el año fuera de rango")
+	}
+}
@@ -0,0 +1,128 @@
+package parser
+
+import "github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+
+// Options configura el comportamiento del parser
+type Options struct {
+	// AutoCorrect reemplaza palabras clave mal escritas por la sugerencia más
+	// parecida cuando no hay ambigüedad ("agenda" → "agendá", "setiembre" → "septiembre")
+	AutoCorrect bool
+
+	// Lenient acepta fechas inexistentes ajustándolas al calendario ("31 de
+	// febrero 2025" → "3 de marzo 2025") en lugar de informar un error
+	// INVALID_DATE; los ajustes se devuelven en Normalizations
+	Lenient bool
+}
+
+// Correction describe una palabra corregida automáticamente
+type Correction struct {
+	Original  string          `json:"original"`
+	Corrected string          `json:"corrected"`
+	Type      lexer.TokenType `json:"type"`
+	Start     int             `json:"start"` // offset en bytes (inclusive)
+	End       int             `json:"end"`   // offset en bytes (exclusivo)
+}
+
+// Corrections devuelve las correcciones automáticas aplicadas durante el análisis
+func (p *Parser) Corrections() []Correction {
+	return p.corrections
+}
+
+// Warnings devuelve los avisos sobre palabras que parecen palabras clave mal
+// escritas y que, sin la autocorrección, quedaron en la descripción. Tienen
+// el formato de los errores, con el código POSSIBLE_TYPO y las sugerencias.
+func (p *Parser) Warnings() []*AnalyzerError {
+	return p.warnings
+}
+
+// corregir registra la corrección del token actual por la mejor sugerencia,
+// si la autocorrección está activada y la sugerencia no es ambigua
+func (p *Parser) corregir(sugerencias []lexer.Suggestion) bool {
+	if !p.opts.AutoCorrect || !lexer.Unambiguous(sugerencias) {
+		return false
+	}
+
+	p.corrections = append(p.corrections, Correction{
+		Original:  p.curToken.Literal,
+		Corrected: sugerencias[0].Word,
+		Type:      sugerencias[0].Type,
+		Start:     p.curToken.Pos,
+		End:       p.curToken.End,
+	})
+	return true
+}
+
+// corregirFechaFinal reinterpreta la última palabra del detalle como fecha
+// cuando parece un día o una fecha relativa mal escrita ("mierkoles a las 10").
+// Sólo se aplica con distancia 1 para no confundir palabras de la descripción;
+// sin la autocorrección la palabra queda en la descripción y, si parece un día
+// de la semana, se registra un aviso con las sugerencias. Después de "el" ("el miercole") la palabra sólo puede ser una fecha: "el"
+// no queda en la descripción y, si la palabra no se puede corregir, se
+// informa el error con las sugerencias. Con la autocorrección también se
+// descarta un artículo ("la mañanna").
+func (p *Parser) corregirFechaFinal() bool {
+	conArticulo := p.curToken.Type == lexer.EL ||
+		(p.opts.AutoCorrect && p.curToken.Type == lexer.PALABRA && p.l.Vocabulary().IsArticle(p.curToken.Literal))
+	n := 0
+	if conArticulo {
+		n = 1
+	}
+	if p.tokenEn(n).Type != lexer.PALABRA {
+		return false
+	}
+	switch p.tokenEn(n + 1).Type {
+	case lexer.ALAS, lexer.HORAFIJA, lexer.EOF:
+	default:
+		return false
+	}
+	if !p.opts.AutoCorrect && !conArticulo {
+		p.avisarDiaMalEscrito()
+		return false
+	}
+
+	sugerencias := p.sugerir(p.tokenEn(n).Literal, lexer.FECHARELATIVA, lexer.DIASEMANA)
+	if len(sugerencias) == 0 || sugerencias[0].Distance > 1 {
+		return false
+	}
+	if conArticulo {
+		p.nextToken()
+	}
+	if !p.corregir(sugerencias) {
+		if !conArticulo {
+			return false
+		}
+		err := p.errorEn(p.curToken, CodigoFechaInvalida, esperaInicioFecha,
+			"se esperaba una fecha, se encontró %s (%s)", p.curToken.Type, p.curToken.Literal)
+		err.Suggestions = sugerencias
+		p.addError(err)
+		p.nextToken()
+		return true
+	}
+
+	// La corrección queda en la lista de tokens por si el parser vuelve atrás
+	p.curToken.Type = sugerencias[0].Type
+	p.curToken.Keyword = sugerencias[0].Word
+	p.tokens[p.position] = p.curToken
+	return true
+}
+
+// avisarDiaMalEscrito registra un aviso POSSIBLE_TYPO si la palabra actual se
+// parece a un día de la semana ("mierkoles" → "miércoles"). El parser puede
+// pasar dos veces por la misma palabra al mirar hacia adelante, así que el
+// aviso se registra una sola vez.
+func (p *Parser) avisarDiaMalEscrito() {
+	sugerencias := p.sugerir(p.curToken.Literal, lexer.DIASEMANA)
+	if len(sugerencias) == 0 || sugerencias[0].Distance > 1 {
+		return
+	}
+	for _, aviso := range p.warnings {
+		if aviso.Start == p.curToken.Pos {
+			return
+		}
+	}
+
+	aviso := p.errorEn(p.curToken, CodigoPosibleErrata, nil,
+		"%s parece un día mal escrito, ¿quisiste decir %s?", p.curToken.Literal, sugerencias[0].Word)
+	aviso.Suggestions = sugerencias
+	p.warnings = append(p.warnings, aviso)
+}
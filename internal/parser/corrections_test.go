@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSugerencias(t *testing.T) {
+	casos := []struct {
+		entrada    string
+		codigo     string
+		sugerencia string
+	}{
+		{"agendx reunión", CodigoVerboInvalido, "agendá"},
+		{"recordame pagar 15 de setiembre", CodigoFechaInvalida, "septiembre"},
+		{"recordame pagar 15 de marzoo", CodigoFechaInvalida, "marzo"},
+		{"agendá reunión el miercole", CodigoFechaInvalida, "miércoles"},
+		{"recordame pagar el lune a las 9", CodigoFechaInvalida, "lunes"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, errs := parsear(c.entrada, Options{})
+			if len(errs) != 1 {
+				t.Fatalf("errores = %v, se esperaba uno", errs)
+			}
+			if errs[0].Code != c.codigo {
+				t.Errorf("código = %s, se esperaba %s", errs[0].Code, c.codigo)
+			}
+			if len(errs[0].Suggestions) == 0 || errs[0].Suggestions[0].Word != c.sugerencia {
+				t.Errorf("sugerencias = %v, se esperaba %q primero", errs[0].Suggestions, c.sugerencia)
+			}
+		})
+	}
+}
+
+func TestAutoCorreccion(t *testing.T) {
+	casos := []struct {
+		entrada     string
+		verbo       string
+		palabras    string
+		fecha, hora string
+		corregidas  string
+	}{
+		{"agendx reunión mañana", "agendá", "reunión", "mañana", "", "agendx→agendá"},
+		{"recordame pagar 15 de setiembre", "recordame", "pagar", "15 de septiembre", "", "setiembre→septiembre"},
+		{"agendá reunión mierkoles a las 10", "agendá", "reunión", "miércoles", "a las 10:00", "mierkoles→miércoles"},
+		// "el" es parte de la fecha corregida, no de la descripción
+		{"agendá reunión el miercole", "agendá", "reunión", "miércoles", "", "miercole→miércoles"},
+		{"agendá reunión el miercole a las 10", "agendá", "reunión", "miércoles", "a las 10:00", "miercole→miércoles"},
+		{"recordame comprar la manzana", "recordame", "comprar", "mañana", "", "manzana→mañana"},
+		// Una palabra que no es la última de la descripción no se corrige
+		{"recordame comprar manzana verde", "recordame", "comprar manzana verde", "", "", ""},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			p := nuevoParser(c.entrada, "es", Options{AutoCorrect: true})
+			comando, err := p.Parse()
+			if err != nil {
+				t.Fatalf("error inesperado: %v", p.Errors())
+			}
+			verbo, detalle, tiempo := partes(t, comando)
+			if verbo.Value != c.verbo {
+				t.Errorf("verbo = %q, se esperaba %q", verbo.Value, c.verbo)
+			}
+			if got := strings.Join(detalle.Palabras, " "); got != c.palabras {
+				t.Errorf("palabras = %q, se esperaba %q", got, c.palabras)
+			}
+			if got := textoFecha(tiempo.Fecha); got != c.fecha {
+				t.Errorf("fecha = %q, se esperaba %q", got, c.fecha)
+			}
+			if got := textoHora(tiempo.Hora); got != c.hora {
+				t.Errorf("hora = %q, se esperaba %q", got, c.hora)
+			}
+			var corregidas []string
+			for _, correccion := range p.Corrections() {
+				if c.entrada[correccion.Start:correccion.End] != correccion.Original {
+					t.Errorf("la corrección %+v no ubica a %q", correccion, correccion.Original)
+				}
+				corregidas = append(corregidas, correccion.Original+"→"+correccion.Corrected)
+			}
+			if got := strings.Join(corregidas, " "); got != c.corregidas {
+				t.Errorf("correcciones = %q, se esperaba %q", got, c.corregidas)
+			}
+		})
+	}
+}
+
+func TestSinAutoCorreccion(t *testing.T) {
+	// Sin "el" una palabra parecida a una fecha sigue siendo parte de la descripción
+	_, detalle, tiempo := sinErrores(t, "recordame comprar la manzana", Options{})
+	if got := strings.Join(detalle.Palabras, " "); got != "comprar la manzana" || tiempo.Fecha != nil {
+		t.Errorf("palabras = %q, fecha = %q", got, textoFecha(tiempo.Fecha))
+	}
+}
+
+func TestAvisoDiaMalEscrito(t *testing.T) {
+	casos := []struct {
+		entrada    string
+		locale     string
+		palabras   string
+		sugerencia string // vacío si no se espera un aviso
+	}{
+		{"agendá reunion mierkoles a las 10", "es", "reunion mierkoles", "miércoles"},
+		{"agendá reunión lune", "es", "reunión lune", "lunes"},
+		{"schedule meeting wednesdy at 10", "en", "meeting wednesdy", "wednesday"},
+		// Sólo se avisa de los días, no de las fechas relativas
+		{"recordame comprar la manzana", "es", "comprar la manzana", ""},
+		{"agendá reunión con el equipo", "es", "reunión con el equipo", ""},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			p := nuevoParser(c.entrada, c.locale, Options{})
+			comando, err := p.Parse()
+			if err != nil {
+				t.Fatalf("errores inesperados: %v", p.Errors())
+			}
+			_, detalle, _ := partes(t, comando)
+			if got := strings.Join(detalle.Palabras, " "); got != c.palabras {
+				t.Errorf("palabras = %q, se esperaba %q", got, c.palabras)
+			}
+			avisos := p.Warnings()
+			if c.sugerencia == "" {
+				if len(avisos) != 0 {
+					t.Errorf("avisos = %v, no se esperaba ninguno", avisos)
+				}
+				return
+			}
+			if len(avisos) != 1 || avisos[0].Code != CodigoPosibleErrata {
+				t.Fatalf("avisos = %v, se esperaba uno %s", avisos, CodigoPosibleErrata)
+			}
+			if avisos[0].Suggestions[0].Word != c.sugerencia {
+				t.Errorf("sugerencias = %v, se esperaba %q primero", avisos[0].Suggestions, c.sugerencia)
+			}
+			if c.entrada[avisos[0].Start:avisos[0].End] != avisos[0].Token {
+				t.Errorf("el aviso %+v no ubica a %q", avisos[0], avisos[0].Token)
+			}
+		})
+	}
+}
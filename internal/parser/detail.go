@@ -0,0 +1,251 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+)
+
+// esPalabra indica si el token es una palabra que puede formar parte de un
+// nombre, un lugar o un tema
+func esPalabra(tok lexer.Token) bool {
+	switch tok.Type {
+	case lexer.PALABRA, lexer.TIPOEVENTO, lexer.TEXTO:
+		return true
+	}
+	return false
+}
+
+// esMayuscula indica si el token es una palabra que empieza con mayúscula
+// ("Juan", "Starbucks")
+func esMayuscula(tok lexer.Token) bool {
+	if tok.Type != lexer.PALABRA && tok.Type != lexer.TIPOEVENTO {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(tok.Literal)
+	return unicode.IsUpper(r)
+}
+
+// empiezaConMayuscula indica si el literal del token empieza con mayúscula,
+// aunque sea una palabra clave: después de "de" es parte del nombre ("Plaza
+// de Mayo")
+func empiezaConMayuscula(tok lexer.Token) bool {
+	r, _ := utf8.DecodeRuneInString(tok.Literal)
+	return tok.Type != lexer.TEXTO && unicode.IsUpper(r)
+}
+
+// esComillaSinCerrar indica si el token es un texto entre comillas al que le
+// falta la comilla de cierre
+func esComillaSinCerrar(tok lexer.Token) bool {
+	r, _ := utf8.DecodeRuneInString(tok.Literal)
+	return tok.Type == lexer.ILLEGAL && strings.ContainsRune(`"“«`, r)
+}
+
+// agregarPalabra agrega el token a las palabras de la descripción. El texto
+// entre comillas se agrega sin ellas, y un token escrito pegado al anterior
+// se une a la última palabra, así "pan," o "PR #42" se guardan como se
+// escribieron.
+func (p *Parser) agregarPalabra(palabras []string, tok lexer.Token) []string {
+	palabra := tok.Literal
+	if tok.Type == lexer.TEXTO {
+		palabra = tok.Keyword
+	}
+	if len(palabras) > 0 && p.pegado(tok) {
+		palabras[len(palabras)-1] += palabra
+		return palabras
+	}
+	if tok.Type == lexer.TEXTO {
+		return append(palabras, palabra)
+	}
+	return append(palabras, strings.Fields(palabra)...)
+}
+
+// pegado indica si el token está escrito sin espacio después del anterior
+func (p *Parser) pegado(tok lexer.Token) bool {
+	anterior, _ := utf8.DecodeLastRuneInString(p.l.Input()[:tok.Pos])
+	return tok.Pos > 0 && !unicode.IsSpace(anterior)
+}
+
+// agregarPalabras agrega a la descripción los tokens entre desde y hasta
+// (inclusive)
+func (p *Parser) agregarPalabras(palabras []string, desde, hasta lexer.Token) []string {
+	for _, tok := range p.tokens {
+		if tok.Pos >= desde.Pos && tok.End <= hasta.End && tok.Type != lexer.EOF {
+			palabras = p.agregarPalabra(palabras, tok)
+		}
+	}
+	return palabras
+}
+
+// esValor indica si desde el token n comienza un VALOR: un texto entre
+// comillas, una palabra, o un artículo seguido de una palabra ("la oficina",
+// "mi hermano")
+func (p *Parser) esValor(n int) bool {
+	tok := p.tokenEn(n)
+	if esPalabra(tok) {
+		return true
+	}
+	return p.esValorArticulo(n)
+}
+
+// parseValor analiza la regla
+//
+//	VALOR         → TEXTO | [ ARTICULO ] ( NOMBRE_PROPIO | PALABRA )
+//	NOMBRE_PROPIO → MAYUSCULA { [ "de" ] MAYUSCULA }
+//
+// y devuelve su texto. Un nombre propio sigue mientras las palabras empiecen
+// con mayúscula ("Juan Pérez", "Plaza de Mayo"); si no, el valor es una sola
+// palabra, así en "almorzar con juan para hablar" el participante es "juan".
+func (p *Parser) parseValor() string {
+	if p.curToken.Type == lexer.TEXTO {
+		valor := p.curToken.Keyword
+		p.nextToken()
+		return valor
+	}
+
+	var partes []string
+	if p.esValorArticulo(0) {
+		partes = append(partes, p.curToken.Literal)
+		p.nextToken()
+	}
+
+	if !esMayuscula(p.curToken) {
+		partes = append(partes, p.curToken.Literal)
+		p.nextToken()
+		return strings.Join(partes, " ")
+	}
+	for {
+		partes = append(partes, p.curToken.Literal)
+		p.nextToken()
+		if p.curToken.Type == lexer.DE && empiezaConMayuscula(p.peekToken) {
+			partes = append(partes, p.curToken.Literal)
+			p.nextToken()
+			continue
+		}
+		if !esMayuscula(p.curToken) && !(p.anterior().Type == lexer.DE && empiezaConMayuscula(p.curToken)) {
+			return strings.Join(partes, " ")
+		}
+	}
+}
+
+// esInicioParticipantes indica si el token actual comienza los participantes:
+// "con" seguido de un valor
+func (p *Parser) esInicioParticipantes() bool {
+	return p.curToken.Type == lexer.CON && p.esValor(1)
+}
+
+// parseParticipantes analiza la regla
+//
+//	PARTICIPANTES → "con" VALOR { ( "," | "y" ) VALOR }
+//
+// Después de "y" o de una coma sigue otro participante si es un texto entre
+// comillas, un nombre propio o lleva artículo, o si todos se escribieron en
+// minúscula ("con juan y ana"); si no, es parte de la descripción ("con Ana y
+// después comprar pan").
+func (p *Parser) parseParticipantes() []string {
+	p.nextToken() // Saltamos "con"
+	minusculas := p.curToken.Type == lexer.PALABRA && !esMayuscula(p.curToken) && !p.esValorArticulo(0)
+
+	participantes := []string{p.parseValor()}
+	for (p.curToken.Type == lexer.Y || p.curToken.Type == lexer.COMA) && p.esValor(1) {
+		siguiente := p.peekToken
+		if !minusculas && siguiente.Type != lexer.TEXTO && !esMayuscula(siguiente) && !p.esValorArticulo(1) {
+			break
+		}
+		p.nextToken()
+		participantes = append(participantes, p.parseValor())
+	}
+	return participantes
+}
+
+// esValorArticulo indica si el valor que comienza en el token n lleva artículo
+func (p *Parser) esValorArticulo(n int) bool {
+	siguiente := p.tokenEn(n + 1)
+	return p.l.Vocabulary().IsArticle(p.tokenEn(n).Literal) && esPalabra(siguiente) && siguiente.Type != lexer.TEXTO
+}
+
+// esInicioLugar indica si el token actual comienza el lugar: "en" seguido de
+// un valor, salvo que comience una fecha ("en 3 días"), o una marca de lugar
+// del vocabulario seguida de un artículo, un nombre propio o un texto entre
+// comillas ("at the office", "at Starbucks", pero "at 5 pm" es la hora)
+func (p *Parser) esInicioLugar() bool {
+	if p.curToken.Type == lexer.EN {
+		return !p.esInicioFecha() && p.esValor(1)
+	}
+	return p.l.Vocabulary().IsPlaceMarker(p.curToken.Literal) &&
+		(p.esValorArticulo(1) || esMayuscula(p.peekToken) || p.peekToken.Type == lexer.TEXTO)
+}
+
+// parseLugar analiza la regla LUGAR → ( "en" | MARCA_LUGAR ) VALOR
+func (p *Parser) parseLugar() string {
+	p.nextToken() // Saltamos "en"
+	return p.parseValor()
+}
+
+// esInicioTema indica si el token actual comienza el tema: "sobre" seguido de
+// un valor
+func (p *Parser) esInicioTema() bool {
+	return p.curToken.Type == lexer.SOBRE && p.esValor(1)
+}
+
+// parseTema analiza la regla
+//
+//	TEMA → "sobre" ( TEXTO | PALABRA { PALABRA } )
+//
+// A diferencia del lugar y los participantes, el tema sigue hasta el tiempo,
+// otro participante o lugar, o el fin del detalle: "sobre el presupuesto de
+// marketing".
+func (p *Parser) parseTema() string {
+	p.nextToken() // Saltamos "sobre"
+	if p.curToken.Type == lexer.TEXTO {
+		return p.parseValor()
+	}
+
+	var tema []string
+	for p.curToken.Type != lexer.TEXTO && !p.esInicioParticipantes() && !p.esInicioLugar() &&
+		!p.esFinComando() && p.esPalabraDetalle() && !p.corregirFechaFinal() {
+		tema = p.agregarPalabra(tema, p.curToken)
+		p.nextToken()
+	}
+	return strings.Join(tema, " ")
+}
+
+// esMarca indica si el token marca la prioridad o una etiqueta de la acción.
+// Una palabra de prioridad pegada a la anterior es parte de la descripción
+// ("reunión-importante").
+func (p *Parser) esMarca(tok lexer.Token) bool {
+	return tok.Type == lexer.ETIQUETA || tok.Type == lexer.PRIORIDAD && !p.pegado(tok)
+}
+
+// parseMarca analiza la regla
+//
+//	MARCA → PRIORIDAD | ETIQUETA
+//
+// y la agrega al detalle. Las marcas pueden ir en cualquier parte del comando
+// y no forman parte de la descripción. Una etiqueta repetida se guarda una
+// sola vez; otra prioridad con un nivel distinto ("urgente ... prioridad
+// baja") es un error PRIORITY_CONFLICT.
+func (p *Parser) parseMarca(detalle *ast.DetalleEvento) {
+	tok := p.curToken
+	p.nextToken()
+
+	if tok.Type == lexer.ETIQUETA {
+		etiqueta := strings.ToLower(tok.Keyword)
+		if !slices.Contains(detalle.Etiquetas, etiqueta) {
+			detalle.Etiquetas = append(detalle.Etiquetas, etiqueta)
+		}
+		return
+	}
+
+	nivel, _ := p.l.Vocabulary().PriorityOf(tok.Keyword)
+	if detalle.Prioridad != "" && detalle.Prioridad != nivel {
+		p.addError(p.errorEn(tok, CodigoConflictoPrioridad, nil,
+			"ya se indicó la prioridad (%s), se encontró otra: %s", detalle.Prioridad, tok.Literal))
+		return
+	}
+	detalle.Prioridad = nivel
+}
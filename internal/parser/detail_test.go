@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetalle(t *testing.T) {
+	casos := []struct {
+		entrada       string
+		locale        string
+		texto         string
+		participantes string // separados por "|"
+		lugar, tema   string
+	}{
+		{"agendá reunión con Laura y Pedro en la oficina sobre el presupuesto mañana", "es", "", "Laura|Pedro", "la oficina", "el presupuesto"},
+		{"agendá almuerzo con Juan Pérez, Ana y Luis en Starbucks Palermo", "es", "almuerzo", "Juan Pérez|Ana|Luis", "Starbucks Palermo", ""},
+		{`agendá visita en "la casa de mi abuela" el sábado`, "es", "visita", "", "la casa de mi abuela", ""},
+		{"agendá paseo en la Plaza de Mayo", "es", "paseo", "", "la Plaza de Mayo", ""},
+		{"recordame hablar sobre el viaje con mamá", "es", "hablar", "mamá", "", "el viaje"},
+		{"agendá cumpleaños de Sofía en casa", "es", "cumpleaños de Sofía", "", "casa", ""},
+		// "en" seguido de una cantidad de tiempo es una fecha
+		{"agendá reunión con Ana en 3 días", "es", "", "Ana", "", ""},
+		// El texto entre comillas se toma tal cual
+		{`anotá "comprar pan en lo de Ana" mañana`, "es", "comprar pan en lo de Ana", "", "", ""},
+		{"schedule meeting with Ana and Bob at the office about the budget tomorrow", "en", "", "Ana|Bob", "the office", "the budget"},
+		{"schedule lunch with John Smith in Palermo", "en", "lunch", "John Smith", "Palermo", ""},
+		// Un apóstrofo dentro de una palabra no la corta
+		{"anotá reunión en O'Higgins", "es", "", "", "O'Higgins", ""},
+		{"agendá almuerzo con María en O’Higgins mañana", "es", "almuerzo", "María", "O’Higgins", ""},
+		{"schedule lunch at Joe's", "en", "lunch", "", "Joe's", ""},
+		{"schedule dinner with Ana at Joe's tomorrow", "en", "dinner", "Ana", "Joe's", ""},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			p := nuevoParser(c.entrada, c.locale, Options{})
+			comando, err := p.Parse()
+			if err != nil {
+				t.Fatalf("errores inesperados: %v", p.Errors())
+			}
+			_, detalle, _ := partes(t, comando)
+			if detalle.Texto != c.texto {
+				t.Errorf("texto = %q, se esperaba %q", detalle.Texto, c.texto)
+			}
+			if got := strings.Join(detalle.Participantes, "|"); got != c.participantes {
+				t.Errorf("participantes = %q, se esperaba %q", got, c.participantes)
+			}
+			if detalle.Lugar != c.lugar || detalle.Tema != c.tema {
+				t.Errorf("lugar = %q, tema = %q, se esperaba %q y %q", detalle.Lugar, detalle.Tema, c.lugar, c.tema)
+			}
+		})
+	}
+}
+
+func TestNombreDelEvento(t *testing.T) {
+	casos := []struct {
+		entrada, tipo, nombre string
+	}{
+		{"agendá cita con Juan", "cita", "Juan"},
+		{"agendá reunión con Laura y Pedro", "reunión", "Laura y Pedro"},
+		{"agendá entrevista de Sofía", "entrevista", "Sofía"},
+		{"agendá almuerzo con Ana", "", ""},
+	}
+
+	for _, c := range casos {
+		_, detalle, _ := sinErrores(t, c.entrada, Options{})
+		if detalle.TipoEvento != c.tipo || detalle.Nombre != c.nombre {
+			t.Errorf("%q: tipo = %q, nombre = %q, se esperaba %q y %q", c.entrada, detalle.TipoEvento, detalle.Nombre, c.tipo, c.nombre)
+		}
+	}
+}
+
+func TestDescripcionConSimbolos(t *testing.T) {
+	casos := []struct {
+		entrada     string
+		palabras    string
+		fecha, hora string
+	}{
+		{"recordame comprar 2 kilos de pan", "comprar 2 kilos de pan", "", ""},
+		{"recordame comprar 2 kilos de pan mañana a las 10", "comprar 2 kilos de pan", "mañana", "a las 10:00"},
+		{"recordame llamar al 0800", "llamar al 0800", "", ""},
+		{"anotá llamar al 15-4444-5555", "llamar al 15-4444-5555", "", ""},
+		{"anotá revisar PR #42", "revisar PR #42", "", ""},
+		{"recordame pagar Netflix (familia)", "pagar Netflix (familia)", "", ""},
+		{"recordame pagar $500 el 10", "pagar $500", "el 10", ""},
+		// Una fecha con barras seguida de una unidad de medida es una cantidad
+		{"anotá comprar 1/2 kilo", "comprar 1/2 kilo", "", ""},
+		{"anotá comprar 3/4 litro de leche mañana", "comprar 3/4 litro de leche", "mañana", ""},
+		{"anotá comprar 1/2 docena de huevos el 1/2", "comprar 1/2 docena de huevos", "01/02", ""},
+		{"recordame pagar 1/2 a las 10", "pagar", "01/02", "a las 10:00"},
+		// Entre comillas no se busca una fecha ni una hora
+		{`anotá "reunión a las 10" mañana`, "reunión a las 10", "mañana", ""},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, detalle, tiempo := sinErrores(t, c.entrada, Options{})
+			if got := strings.Join(detalle.Palabras, " "); got != c.palabras {
+				t.Errorf("palabras = %q, se esperaba %q", got, c.palabras)
+			}
+			if got := textoFecha(tiempo.Fecha); got != c.fecha {
+				t.Errorf("fecha = %q, se esperaba %q", got, c.fecha)
+			}
+			if got := textoHora(tiempo.Hora); got != c.hora {
+				t.Errorf("hora = %q, se esperaba %q", got, c.hora)
+			}
+		})
+	}
+
+	// Unas comillas sin cerrar son un único error, aunque no quede otro detalle
+	for _, entrada := range []string{`anotá "comprar pan mañana`, `anotá "sin cerrar`, `anotá pan "sin cerrar`} {
+		if _, errs := parsear(entrada, Options{}); len(errs) != 1 || errs[0].Code != CodigoSintaxis {
+			t.Errorf("%s: errores = %v, se esperaba sólo %s", entrada, errs, CodigoSintaxis)
+		}
+	}
+}
+
+func TestMarcas(t *testing.T) {
+	casos := []struct {
+		entrada   string
+		locale    string
+		palabras  string
+		prioridad string
+		etiquetas string // separadas por "|"
+	}{
+		{"agendá reunión urgente mañana #trabajo", "es", "reunión", "urgente", "trabajo"},
+		{"recordame pagar la luz prioridad alta #casa #Casa", "es", "pagar la luz", "alta", "casa"},
+		{"anotá comprar pan #super sin apuro", "es", "comprar pan", "baja", "super"},
+		{"#trabajo agendá reunión mañana", "es", "reunión", "", "trabajo"},
+		// Dos palabras del mismo nivel no son un conflicto
+		{"agendá reunión importante prioridad alta", "es", "reunión", "alta", ""},
+		// Pegada a otra palabra es parte de la descripción
+		{"agendá reunión-importante mañana", "es", "reunión-importante", "", ""},
+		{"schedule meeting tomorrow high priority #work", "en", "meeting", "alta", "work"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			p := nuevoParser(c.entrada, c.locale, Options{})
+			comando, err := p.Parse()
+			if err != nil {
+				t.Fatalf("errores inesperados: %v", p.Errors())
+			}
+			_, detalle, _ := partes(t, comando)
+			if got := strings.Join(detalle.Palabras, " "); got != c.palabras {
+				t.Errorf("palabras = %q, se esperaba %q", got, c.palabras)
+			}
+			if detalle.Prioridad != c.prioridad {
+				t.Errorf("prioridad = %q, se esperaba %q", detalle.Prioridad, c.prioridad)
+			}
+			if got := strings.Join(detalle.Etiquetas, "|"); got != c.etiquetas {
+				t.Errorf("etiquetas = %q, se esperaba %q", got, c.etiquetas)
+			}
+		})
+	}
+}
+
+func TestConflictoPrioridad(t *testing.T) {
+	_, errs := parsear("recordame llamar a Ana urgente mañana prioridad baja", Options{})
+	if len(errs) != 1 || errs[0].Code != CodigoConflictoPrioridad {
+		t.Fatalf("errores = %v, se esperaba %s", errs, CodigoConflictoPrioridad)
+	}
+	if errs[0].Token != "prioridad baja" {
+		t.Errorf("token = %q, se esperaba %q", errs[0].Token, "prioridad baja")
+	}
+}
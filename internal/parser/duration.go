@@ -0,0 +1,278 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+)
+
+// minutosDia son los minutos de un día
+const minutosDia = 24 * 60
+
+// esInicioRango indica si el token actual comienza un rango de horas sin "a
+// las": "de 10 a 12", "desde las 9", "from 2 to 4 pm". "de" sólo comienza un
+// rango si le sigue la hora de fin; "desde", también si le sigue una hora con
+// dígitos.
+func (p *Parser) esInicioRango() bool {
+	switch p.curToken.Type {
+	case lexer.DE:
+		return p.sigueRango(1)
+	case lexer.DESDE:
+		return p.sigueRango(1) || (p.peekToken.Type == lexer.NUMERO && !esNumeroEscrito(p.peekToken))
+	}
+	return false
+}
+
+// sigueRango indica si desde el token n hay una hora seguida del separador y
+// la hora de fin ("10:30 a 12", "2 to 4 pm", "10 al mediodía")
+func (p *Parser) sigueRango(n int) bool {
+	if p.tokenEn(n).Type != lexer.NUMERO {
+		return false
+	}
+	for n++; ; n++ {
+		tipo, siguiente := p.tokenEn(n).Type, p.tokenEn(n+1).Type
+		switch tipo {
+		case lexer.COLON, lexer.NUMERO, lexer.PERIODO, lexer.FRACCION, lexer.Y:
+			// Minutos y periodo de la hora de inicio
+		case lexer.MENOS:
+			if siguiente == lexer.FRACCION {
+				continue // "10 menos cuarto a 12"
+			}
+			return siguiente == lexer.NUMERO
+		case lexer.HASTA:
+			return siguiente == lexer.NUMERO || siguiente == lexer.HORAFIJA
+		case lexer.ALAS, lexer.GUION:
+			return siguiente == lexer.NUMERO
+		case lexer.HORAFIJA:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// esFinRango indica si el token actual comienza la hora de fin de un rango
+func (p *Parser) esFinRango(conInicio bool) bool {
+	switch p.curToken.Type {
+	case lexer.HASTA:
+		return p.peekToken.Type == lexer.NUMERO || p.peekToken.Type == lexer.HORAFIJA
+	case lexer.GUION:
+		return p.peekToken.Type == lexer.NUMERO
+	case lexer.ALAS, lexer.MENOS, lexer.HORAFIJA:
+		// Sólo después de "de" o "desde": "de 10 a las 12", "from 2 to 4",
+		// "de 10 al mediodía"
+		return conInicio && (p.curToken.Type == lexer.HORAFIJA || p.peekToken.Type == lexer.NUMERO)
+	}
+	return false
+}
+
+// parseRangoHora analiza la regla
+//
+//	RANGO_HORA → HORA [ SEPARADOR VALOR_HORA | HORA_FIJA ]
+//	           | ( "de" | "desde" ) VALOR_HORA [ SEPARADOR VALOR_HORA | HORA_FIJA ]
+//	SEPARADOR  → "a" | "a las" | "hasta" | "hasta las" | "-"
+//
+// y devuelve la hora de inicio y, si se indicó, la de fin. Si sólo la hora de
+// fin tiene periodo, vale también para el inicio ("de 2 a 4 pm"). Si la hora
+// de fin es anterior a la de inicio el rango termina al día siguiente ("de 22
+// a 1", "de 20 a medianoche"); sólo un rango sin duración es un error.
+func (p *Parser) parseRangoHora() (*ast.Hora, *ast.Hora, *AnalyzerError) {
+	desde := p.curToken
+	conInicio := p.curToken.Type == lexer.DE || p.curToken.Type == lexer.DESDE
+
+	inicio := &ast.Hora{}
+	if conInicio {
+		p.nextToken()
+		if err := p.parseValorHora(inicio, true); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var err *AnalyzerError
+		if inicio, err = p.parseHora(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !p.esFinRango(conInicio) {
+		return inicio, nil, nil
+	}
+
+	var fin *ast.Hora
+	switch p.curToken.Type {
+	case lexer.ALAS, lexer.HORAFIJA:
+		var err *AnalyzerError
+		if fin, err = p.parseHora(); err != nil {
+			return nil, nil, err
+		}
+	default:
+		p.nextToken() // Saltamos el separador
+		if p.curToken.Type == lexer.HORAFIJA {
+			var err *AnalyzerError
+			if fin, err = p.parseHora(); err != nil {
+				return nil, nil, err
+			}
+			break
+		}
+		fin = &ast.Hora{}
+		if err := p.parseValorHora(fin, false); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	vocabulario := p.l.Vocabulary()
+	if inicio.Periodo == "" && inicio.Nombre == "" && fin.Periodo != "" {
+		if _, ok := vocabulario.ResolveHour(fin.Periodo, inicio.Hora); ok {
+			inicio.Periodo = fin.Periodo
+		}
+	}
+
+	desdeMin, ok1 := minutosDelDia(vocabulario, inicio)
+	hastaMin, ok2 := minutosDelDia(vocabulario, fin)
+	if _, ok := RangeMinutes(desdeMin, hastaMin); ok1 && ok2 && !ok {
+		if hastaMin == desdeMin {
+			p.addError(p.errorEntre(desde, p.anterior(), CodigoHoraInvalida, nil,
+				"la hora de fin (%s) es igual a la de inicio", formatoMinutos(hastaMin)))
+		} else {
+			p.addError(p.errorEntre(desde, p.anterior(), CodigoHoraInvalida, nil,
+				"la hora de fin (%s) debe ser posterior a la de inicio (%s)", formatoMinutos(hastaMin), formatoMinutos(desdeMin)))
+		}
+	}
+	return inicio, fin, nil
+}
+
+// minutosDelDia convierte la hora en minutos desde la medianoche, con el
+// periodo aplicado como al crear la acción. Devuelve false si el periodo no
+// corresponde a la hora (ese error ya se informó al leerla).
+func minutosDelDia(v *vocab.Vocabulary, hora *ast.Hora) (int, bool) {
+	h := hora.Hora
+	if hora.Periodo != "" {
+		var ok bool
+		if h, ok = v.ResolveHour(hora.Periodo, hora.Hora); !ok {
+			return 0, false
+		}
+	}
+	return (h*60 + hora.Minutos - hora.Menos + minutosDia) % minutosDia, true
+}
+
+// Un rango con la hora de fin anterior a la de inicio cruza la medianoche si
+// empieza a la noche y termina antes del mediodía ("de 22 a 1", "de 20 a 8")
+const (
+	inicioNoche  = 18 * 60
+	finMadrugada = 12 * 60
+)
+
+// RangeMinutes devuelve cuántos minutos dura un rango de horas, con el inicio
+// y el fin en minutos desde la medianoche. Un fin anterior al inicio sólo se
+// acepta si el rango cruza la medianoche: si termina a medianoche ("de 20 a
+// 00:00") o si empieza a la noche y termina antes del mediodía ("de 22 a 1").
+// Devuelve false si el fin es igual al inicio o no es posterior ("de 11 a 10").
+func RangeMinutes(desde, hasta int) (int, bool) {
+	switch {
+	case hasta > desde:
+		return hasta - desde, true
+	case hasta == 0 && desde > 0, desde >= inicioNoche && hasta < finMadrugada:
+		return hasta + minutosDia - desde, true
+	}
+	return 0, false
+}
+
+// formatoMinutos escribe los minutos desde la medianoche como "HH:MM"
+func formatoMinutos(minutos int) string {
+	return fmt.Sprintf("%02d:%02d", minutos/60, minutos%60)
+}
+
+// esInicioDuracion indica si el token actual comienza una duración:
+// "durante" o "por" seguido de una cantidad y una unidad ("durante 2 horas"),
+// una fracción y una unidad ("por media hora") o una unidad y una fracción
+// ("durante hora y media")
+func (p *Parser) esInicioDuracion() bool {
+	if p.curToken.Type != lexer.DURANTE {
+		return false
+	}
+	switch p.peekToken.Type {
+	case lexer.NUMERO, lexer.FRACCION:
+		_, ok := p.unidadDuracion(p.tokenEn(2))
+		return ok
+	}
+	_, ok := p.unidadDuracion(p.peekToken)
+	return ok && p.tokenEn(2).Type == lexer.Y && p.tokenEn(3).Type == lexer.FRACCION
+}
+
+// unidadDuracion devuelve los minutos de una unidad de duración: "hora" y
+// "minutos", también "horas" aunque se lea como periodo, y los días y
+// semanas. Los meses y años no tienen una cantidad fija de minutos.
+func (p *Parser) unidadDuracion(tok lexer.Token) (int, bool) {
+	vocabulario := p.l.Vocabulary()
+	switch tok.Type {
+	case lexer.DURACION, lexer.PERIODO:
+		return vocabulario.DurationMinutes(tok.Keyword)
+	case lexer.UNIDAD:
+		switch unidad, _ := vocabulario.UnitOf(tok.Keyword); unidad {
+		case vocab.UnitDay:
+			return minutosDia, true
+		case vocab.UnitWeek:
+			return 7 * minutosDia, true
+		}
+	}
+	return 0, false
+}
+
+// parseDuracion analiza la regla
+//
+//	DURACION → ( "durante" | "por" ) ( CANTIDAD [ "y" ( FRACCION | CANTIDAD ) ]
+//	         | UNIDAD_DURACION "y" FRACCION )
+//	CANTIDAD → ( NUMERO | FRACCION ) UNIDAD_DURACION
+//
+// La fracción después de "y" es de la última unidad: "una hora y media" son
+// 90 minutos. Sólo se llega aquí si esInicioDuracion vio una de esas formas.
+func (p *Parser) parseDuracion() (*ast.Duracion, *AnalyzerError) {
+	vocabulario := p.l.Vocabulary()
+	desde := p.curToken
+	p.nextToken()
+
+	total, unidad := 0, 0
+	switch p.curToken.Type {
+	case lexer.NUMERO:
+		cantidad := valorNumero(p.curToken)
+		p.nextToken()
+		unidad, _ = p.unidadDuracion(p.curToken)
+		total = cantidad * unidad
+		p.nextToken()
+	case lexer.FRACCION:
+		minutos, _ := vocabulario.FractionMinutes(p.curToken.Keyword)
+		p.nextToken()
+		unidad, _ = p.unidadDuracion(p.curToken)
+		total = minutos * unidad / 60
+		p.nextToken()
+	default:
+		// "durante hora y media": la unidad sola es una
+		unidad, _ = p.unidadDuracion(p.curToken)
+		total = unidad
+		p.nextToken()
+	}
+
+	if p.curToken.Type == lexer.Y {
+		switch {
+		case p.peekToken.Type == lexer.FRACCION:
+			p.nextToken()
+			minutos, _ := vocabulario.FractionMinutes(p.curToken.Keyword)
+			total += minutos * unidad / 60
+			p.nextToken()
+		case p.peekToken.Type == lexer.NUMERO:
+			if otra, ok := p.unidadDuracion(p.tokenEn(2)); ok {
+				p.nextToken()
+				total += valorNumero(p.curToken) * otra
+				p.nextToken()
+				p.nextToken()
+			}
+		}
+	}
+
+	if total < 1 {
+		return nil, p.errorEntre(desde, p.anterior(), CodigoDuracionInvalida, nil,
+			"la duración debe ser mayor que cero")
+	}
+	return &ast.Duracion{Valor: p.l.Input()[desde.Pos:p.anterior().End], Minutos: total}, nil
+}
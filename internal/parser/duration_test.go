@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRangoHora(t *testing.T) {
+	casos := []struct {
+		entrada   string
+		hora, fin string
+		palabras  string
+	}{
+		{"agendá reunión mañana de 10:00 a 11:30", "a las 10:00", "a las 11:30", "reunión"},
+		{"agendá reunión desde las 9 hasta las 12", "a las 09:00", "a las 12:00", "reunión"},
+		{"agendá reunión a las 10 hasta las 11", "a las 10:00", "a las 11:00", "reunión"},
+		{"agendá reunión de 2 a 4 de la tarde", "a las 02:00 de la tarde", "a las 04:00 de la tarde", "reunión"},
+		{"agendá fiesta el sábado de 20 a medianoche", "a las 20:00", "a medianoche", "fiesta"},
+		// Un rango que empieza a la noche puede terminar al día siguiente
+		{"agendá guardia el sábado de 22 a 1", "a las 22:00", "a las 01:00", "guardia"},
+		{"agendá guardia de 20 a 8", "a las 20:00", "a las 08:00", "guardia"},
+		{"agendá guardia de 10 a 00:00", "a las 10:00", "a las 00:00", "guardia"},
+		// "de" sin hora de fin es parte de la descripción
+		{"agendá reunión de trabajo", "", "", "reunión de trabajo"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, detalle, tiempo := sinErrores(t, c.entrada, Options{})
+			if got := textoHora(tiempo.Hora); got != c.hora {
+				t.Errorf("hora = %q, se esperaba %q", got, c.hora)
+			}
+			if got := textoHora(tiempo.HoraFin); got != c.fin {
+				t.Errorf("hora de fin = %q, se esperaba %q", got, c.fin)
+			}
+			if got := strings.Join(detalle.Palabras, " "); got != c.palabras {
+				t.Errorf("palabras = %q, se esperaba %q", got, c.palabras)
+			}
+		})
+	}
+}
+
+func TestRangoHoraInvalido(t *testing.T) {
+	casos := []struct {
+		entrada string
+		codigo  string
+		token   string
+	}{
+		{"agendá guardia de 10 a 10", CodigoHoraInvalida, "de 10 a 10"},
+		{"agendá reunión mañana de 11 a 10", CodigoHoraInvalida, "de 11 a 10"},
+		{"agendá guardia de 12 a 10", CodigoHoraInvalida, "de 12 a 10"},
+		{"agendá guardia de 22 a 21", CodigoHoraInvalida, "de 22 a 21"},
+		{"agendá guardia de 22 a 10 de la noche", CodigoHoraInvalida, "de 22 a 10 de la noche"},
+		{"agendá guardia de 10 a 11 durante 2 horas", CodigoDuracionInvalida, "durante 2 horas"},
+		{"agendá guardia por 0 minutos", CodigoDuracionInvalida, "por 0 minutos"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, errs := parsear(c.entrada, Options{})
+			if len(errs) != 1 || errs[0].Code != c.codigo || errs[0].Token != c.token {
+				t.Errorf("errores = %v, se esperaba %s en %q", errs, c.codigo, c.token)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"unicode/utf8"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+)
+
+// Códigos de error del analizador
+const (
+	CodigoComandoVacio        = "EMPTY_COMMAND"
+	CodigoSintaxis            = "SYNTAX_ERROR"
+	CodigoVerboInvalido       = "INVALID_VERB"
+	CodigoDetalleFaltante     = "MISSING_DETAIL"
+	CodigoFechaInvalida       = "INVALID_DATE"
+	CodigoHoraInvalida        = "INVALID_TIME"
+	CodigoTokenInesperado     = "UNEXPECTED_TOKEN"
+	CodigoRecurrenciaInvalida = "INVALID_RECURRENCE"
+	CodigoDuracionInvalida    = "INVALID_DURATION"
+	CodigoConflictoTiempo     = "TIME_CONFLICT"
+	CodigoConflictoPrioridad  = "PRIORITY_CONFLICT"
+
+	// Código de los avisos: el comando es válido pero una palabra parece mal escrita
+	CodigoPosibleErrata = "POSSIBLE_TYPO"
+)
+
+// AnalyzerError representa un error del analizador con la posición exacta
+// del problema dentro del comando y los tokens que la gramática esperaba.
+type AnalyzerError struct {
+	Code      string   `json:"type"`
+	Message   string   `json:"message"`
+	Position  int      `json:"position"`  // igual a RuneStart, se mantiene por compatibilidad
+	Start     int      `json:"start"`     // offset en bytes (inclusive)
+	End       int      `json:"end"`       // offset en bytes (exclusivo)
+	RuneStart int      `json:"runeStart"` // offset en caracteres (inclusive)
+	RuneEnd   int      `json:"runeEnd"`   // offset en caracteres (exclusivo)
+	Token     string   `json:"token"`
+	Expected  []string `json:"expected,omitempty"`
+
+	// Suggestions son las palabras clave parecidas al token, de la más a la menos parecida
+	Suggestions []lexer.Suggestion `json:"suggestions,omitempty"`
+}
+
+// Error implementa la interfaz error
+func (e *AnalyzerError) Error() string {
+	return e.Message
+}
+
+// NewAnalyzerError crea un error que abarca el texto input[start:end]
+func NewAnalyzerError(input, code string, start, end int, token string, expected []string, message string) *AnalyzerError {
+	runeStart := utf8.RuneCountInString(input[:start])
+	return &AnalyzerError{
+		Code:      code,
+		Message:   message,
+		Position:  runeStart,
+		Start:     start,
+		End:       end,
+		RuneStart: runeStart,
+		RuneEnd:   runeStart + utf8.RuneCountInString(input[start:end]),
+		Token:     token,
+		Expected:  expected,
+	}
+}
+
+// errorEn crea un error ubicado en el token indicado
+func (p *Parser) errorEn(tok lexer.Token, code string, expected []string, format string, args ...interface{}) *AnalyzerError {
+	return p.errorEntre(tok, tok, code, expected, format, args...)
+}
+
+// errorEntre crea un error que abarca desde el token desde hasta el token hasta
+func (p *Parser) errorEntre(desde, hasta lexer.Token, code string, expected []string, format string, args ...interface{}) *AnalyzerError {
+	return &AnalyzerError{
+		Code:      code,
+		Message:   i18n.Sprintf(p.locale(), format, args...),
+		Position:  desde.Column,
+		Start:     desde.Pos,
+		End:       hasta.End,
+		RuneStart: desde.Column,
+		RuneEnd:   hasta.EndColumn,
+		Token:     p.l.Input()[desde.Pos:hasta.End],
+		Expected:  expected,
+	}
+}
+
+// Conjuntos de tokens esperados en cada punto de la gramática
+var (
+	esperaVerbo       = []string{lexer.VERBO}
+	esperaDetalle     = []string{lexer.TIPOEVENTO, lexer.PALABRA}
+	esperaInicioFecha = []string{lexer.FECHARELATIVA, lexer.DIASEMANA, lexer.NUMERO, lexer.MES,
+		lexer.FINPERIODO, lexer.MODIFICADOR, lexer.EN}
+	esperaDiaSemana = []string{lexer.DIASEMANA}
+	esperaDeOMes    = []string{lexer.DE, lexer.MES}
+	esperaMes       = []string{lexer.MES}
+	esperaNumero    = []string{lexer.NUMERO}
+	esperaMesUnidad = []string{lexer.UNIDAD}
+)
+
+// esperadosTrasTiempo calcula los tokens que pueden seguir a una parte del
+// tiempo: una palabra de la descripción o una parte del tiempo no vista
+func esperadosTrasTiempo(vistas partesTiempo) []string {
+	esperados := []string{lexer.PALABRA, lexer.TIPOEVENTO, lexer.TEXTO, lexer.CON, lexer.SOBRE, lexer.PRIORIDAD,
+		lexer.ETIQUETA, lexer.DE, lexer.EL, lexer.Y, lexer.MENOS, lexer.FRACCION, lexer.UNIDAD}
+	if !vistas.fecha {
+		esperados = append(esperados, esperaInicioFecha...)
+	}
+	if !vistas.hora {
+		esperados = append(esperados, lexer.ALAS, lexer.HORAFIJA, lexer.DESDE)
+	}
+	if !vistas.duracion {
+		esperados = append(esperados, lexer.DURANTE)
+	}
+	if !vistas.recurrencia {
+		esperados = append(esperados, lexer.CADA)
+	}
+	return append(esperados, lexer.EOF)
+}
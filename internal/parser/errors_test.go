@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+)
+
+func TestErrorPosicion(t *testing.T) {
+	casos := []struct {
+		entrada            string
+		codigo             string
+		start, end         int
+		runeStart, runeEnd int
+		token              string
+		esperado           string
+	}{
+		// Los offsets en bytes cuentan la tilde de "agendá" y "reunión"
+		{"agendá reunión a las 25:00", CodigoHoraInvalida, 23, 25, 21, 23, "25", ""},
+		{"agendá reunión mañana a las", CodigoHoraInvalida, 30, 30, 27, 27, "", lexer.NUMERO},
+		{"recordame pagar 15 de marzoo", CodigoFechaInvalida, 22, 28, 22, 28, "marzoo", lexer.MES},
+		{"mañana reunión", CodigoVerboInvalido, 8, 16, 7, 14, "reunión", lexer.VERBO},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, errs := parsear(c.entrada, Options{})
+			if len(errs) == 0 {
+				t.Fatal("se esperaba un error")
+			}
+			err := errs[0]
+			if err.Code != c.codigo {
+				t.Errorf("código = %s, se esperaba %s", err.Code, c.codigo)
+			}
+			if err.Start != c.start || err.End != c.end || err.RuneStart != c.runeStart || err.RuneEnd != c.runeEnd {
+				t.Errorf("posición = [%d,%d) runas [%d,%d), se esperaba [%d,%d) runas [%d,%d)",
+					err.Start, err.End, err.RuneStart, err.RuneEnd, c.start, c.end, c.runeStart, c.runeEnd)
+			}
+			if err.Position != err.RuneStart {
+				t.Errorf("position = %d, debe ser igual a runeStart %d", err.Position, err.RuneStart)
+			}
+			if err.Token != c.token || c.entrada[err.Start:err.End] != c.token {
+				t.Errorf("token = %q, se esperaba %q", err.Token, c.token)
+			}
+			if c.esperado != "" && !slices.Contains(err.Expected, c.esperado) {
+				t.Errorf("esperados = %v, falta %s", err.Expected, c.esperado)
+			}
+		})
+	}
+}
+
+func TestNewAnalyzerError(t *testing.T) {
+	err := NewAnalyzerError("añadí algo", CodigoVerboInvalido, 0, 7, "añadí", []string{lexer.VERBO}, "verbo inválido")
+	if err.RuneStart != 0 || err.RuneEnd != 5 || err.Start != 0 || err.End != 7 {
+		t.Errorf("posición = [%d,%d) runas [%d,%d)", err.Start, err.End, err.RuneStart, err.RuneEnd)
+	}
+	if err.Error() != "verbo inválido" {
+		t.Errorf("mensaje = %q", err.Error())
+	}
+}
+
+func TestRecuperacion(t *testing.T) {
+	casos := []struct {
+		entrada string
+		codigos []string
+	}{
+		{"agendá a las 25", []string{CodigoHoraInvalida, CodigoDetalleFaltante}},
+		{"agendá reunión a las 25:00 el 32 de marzo", []string{CodigoHoraInvalida, CodigoFechaInvalida}},
+		{"recordame el 32 de marzo comprar pan a las 9:75", []string{CodigoFechaInvalida, CodigoHoraInvalida}},
+		{"agendx reunión mañana a las 10", []string{CodigoVerboInvalido}},
+		{"agendá reunión mañana a las 10 25:00 extra", []string{CodigoTokenInesperado}},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			comando, errs := parsear(c.entrada, Options{})
+			if comando != nil {
+				t.Errorf("se esperaba un error, se obtuvo %+v", comando)
+			}
+			var codigos []string
+			for _, err := range errs {
+				codigos = append(codigos, err.Code)
+			}
+			if !slices.Equal(codigos, c.codigos) {
+				t.Errorf("códigos = %v, se esperaba %v", codigos, c.codigos)
+			}
+		})
+	}
+}
+
+func TestRecuperacionLlegaAlFinal(t *testing.T) {
+	// Después del error el análisis sigue hasta el final del comando
+	p := nuevoParser("recordame el 32 de marzo comprar pan", "es", Options{})
+	p.ParseComando()
+	if len(p.Errors()) != 1 {
+		t.Fatalf("errores = %v, se esperaba uno", p.Errors())
+	}
+	if p.curToken.Type != lexer.EOF {
+		t.Errorf("el análisis se detuvo en %s", p.curToken.Literal)
+	}
+}
@@ -1,8 +1,8 @@
 package parser
 
 import (
-	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
 	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
@@ -10,20 +10,30 @@ import (
 
 // Parser representa el analizador sintáctico
 type Parser struct {
-	l         *lexer.Lexer
-	tokens    []lexer.Token
-	position  int
-	errors    []string
-	curToken  lexer.Token
-	peekToken lexer.Token
+	l              *lexer.Lexer
+	tokens         []lexer.Token
+	position       int
+	errors         []*AnalyzerError
+	curToken       lexer.Token
+	peekToken      lexer.Token
+	opts           Options
+	corrections    []Correction
+	normalizations []Normalization
+	warnings       []*AnalyzerError
 }
 
 // New crea un nuevo Parser
 func New(l *lexer.Lexer) *Parser {
+	return NewWithOptions(l, Options{})
+}
+
+// NewWithOptions crea un nuevo Parser con las opciones indicadas
+func NewWithOptions(l *lexer.Lexer, opts Options) *Parser {
 	p := &Parser{
 		l:      l,
 		tokens: l.Tokenize(),
-		errors: []string{},
+		errors: []*AnalyzerError{},
+		opts:   opts,
 	}
 
 	// Leemos dos tokens para inicializar curToken y peekToken
@@ -41,227 +51,978 @@ func New(l *lexer.Lexer) *Parser {
 func (p *Parser) nextToken() {
 	p.position++
 	if p.position >= len(p.tokens) {
-		p.curToken = lexer.Token{Type: lexer.EOF, Literal: ""}
-		p.peekToken = lexer.Token{Type: lexer.EOF, Literal: ""}
+		p.curToken = p.eof()
+		p.peekToken = p.eof()
 	} else {
 		p.curToken = p.tokens[p.position]
 		if p.position+1 < len(p.tokens) {
 			p.peekToken = p.tokens[p.position+1]
 		} else {
-			p.peekToken = lexer.Token{Type: lexer.EOF, Literal: ""}
+			p.peekToken = p.eof()
 		}
 	}
 }
 
-// Errors devuelve los errores encontrados durante el análisis
-func (p *Parser) Errors() []string {
+// eof devuelve el token de fin de entrada, con su posición
+func (p *Parser) eof() lexer.Token {
+	if len(p.tokens) > 0 {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return lexer.Token{Type: lexer.EOF, Literal: ""}
+}
+
+// tokenEn devuelve el token n posiciones después del actual (0 es el actual)
+func (p *Parser) tokenEn(n int) lexer.Token {
+	if p.position+n < len(p.tokens) {
+		return p.tokens[p.position+n]
+	}
+	return p.eof()
+}
+
+// anterior devuelve el último token consumido
+func (p *Parser) anterior() lexer.Token {
+	if p.position > 0 && p.position <= len(p.tokens) {
+		return p.tokens[p.position-1]
+	}
+	return p.curToken
+}
+
+// locale devuelve el idioma del vocabulario del comando, usado para traducir
+// los mensajes de error
+func (p *Parser) locale() string {
+	return p.l.Vocabulary().Locale
+}
+
+// sugerir busca palabras clave parecidas en el vocabulario del comando
+func (p *Parser) sugerir(word string, clases ...lexer.TokenType) []lexer.Suggestion {
+	return lexer.SuggestIn(p.l.Vocabulary(), word, clases...)
+}
+
+// Errors devuelve todos los errores encontrados durante el análisis, en el
+// orden en que aparecen en el comando
+func (p *Parser) Errors() []*AnalyzerError {
 	return p.errors
 }
 
 // addError añade un error a la lista de errores
-func (p *Parser) addError(msg string) {
-	p.errors = append(p.errors, msg)
+func (p *Parser) addError(err *AnalyzerError) {
+	p.errors = append(p.errors, err)
 }
 
-// ParseComando analiza un comando completo
+// ParseComando analiza la regla
+//
+//	COMANDO → { TIEMPO | MARCA } VERBO { DETALLE | TIEMPO }
+//
+// hasta el fin de la entrada o hasta donde comienza otro comando (ver
+// ParseSentencias).
+//
+// El tiempo puede ir antes del verbo ("mañana a las 10 agendá dentista"),
+// entre el verbo y la descripción o repartido en la oración ("agendá el lunes
+// reunión a las 9"); sus partes se juntan en un único ast.Tiempo y la
+// descripción sigue siendo una sola aunque el tiempo la corte.
+//
+// El análisis no se detiene en el primer error: cada producción registra sus
+// errores con addError y el parser se resincroniza en el siguiente token de
+// fecha u hora o palabra de la descripción (modo pánico), de modo que
+// Errors() reporta todos los problemas del comando. Si hubo errores se
+// devuelve el primero.
 func (p *Parser) ParseComando() (*ast.Comando, error) {
-	comando := &ast.Comando{}
+	inicio := len(p.errors)
+	comando := &ast.Comando{Locale: p.locale()}
+	tiempo := &ast.Tiempo{}
+	detalle := &ast.DetalleEvento{}
+	var vistas partesTiempo
+
+	// Tiempo, prioridad y etiquetas antes del verbo
+	for p.esInicioTiempo() || p.esMarca(p.curToken) {
+		if p.esMarca(p.curToken) {
+			p.parseMarca(detalle)
+			continue
+		}
+		p.parseParteTiempo(tiempo, &vistas)
+	}
 
-	// Parseamos el verbo
+	// Parseamos el verbo. Si falta y el token es una palabra (por ejemplo un
+	// verbo mal escrito), la tomamos como verbo para seguir con el detalle.
 	verbo, err := p.parseVerbo()
 	if err != nil {
-		return nil, err
+		p.addError(err)
+		if p.curToken.Type == lexer.PALABRA {
+			p.nextToken()
+		}
 	}
 	comando.Verbo = verbo
 
-	// Parseamos el detalle
-	detalle, err := p.parseDetalle()
-	if err != nil {
-		return nil, err
+	// Parseamos el detalle y el tiempo, en cualquier orden
+	trasVerbo := p.curToken
+	detalleConError := false
+	for p.curToken.Type != lexer.EOF && !p.esFinComando() {
+		switch {
+		case p.esInicioTiempo():
+			p.parseParteTiempo(tiempo, &vistas)
+		case p.curToken.Type == lexer.COMA && p.enSiguiente(p.esInicioTiempo):
+			p.nextToken() // "agendá reunión, mañana a las 10"
+		case p.esInicioDetalle():
+			if err := p.parseDetalle(detalle); err != nil {
+				p.addError(err)
+				detalleConError = true
+			}
+		default:
+			// Tokens que no pueden aparecer aquí: los reportamos juntos
+			desde := p.curToken
+			hasta := p.sincronizar()
+			mensaje := "tokens inesperados: %v"
+			if p.curToken.Type == lexer.EOF {
+				mensaje = "tokens inesperados al final: %v"
+			}
+			err := p.errorEntre(desde, hasta, CodigoTokenInesperado,
+				esperadosTrasTiempo(vistas), mensaje, p.literales(desde, hasta))
+			err.Suggestions = p.sugerir(desde.Literal, lexer.FECHARELATIVA, lexer.DIASEMANA, lexer.MES)
+			p.addError(err)
+		}
 	}
-	comando.Detalle = detalle
 
-	// Parseamos el tiempo (opcional)
-	tiempo, err := p.parseTiempo()
-	if err != nil {
-		return nil, err
+	// Si no hay tipo de evento ni texto, es un error, salvo que ya se haya
+	// informado el del detalle (unas comillas sin cerrar)
+	if len(detalle.Palabras) == 0 && !detalleConError {
+		p.addError(p.errorEn(trasVerbo, CodigoDetalleFaltante, esperaDetalle,
+			"se esperaba un detalle de evento o texto, se encontró %s", trasVerbo.Type))
 	}
+	comando.Detalle = detalle
 	comando.Tiempo = tiempo
 
+	if len(p.errors) > inicio {
+		return nil, p.errors[inicio]
+	}
 	return comando, nil
 }
 
 // parseVerbo analiza un verbo
-func (p *Parser) parseVerbo() (*ast.Verbo, error) {
+func (p *Parser) parseVerbo() (*ast.Verbo, *AnalyzerError) {
 	if p.curToken.Type != lexer.VERBO {
-		return nil, fmt.Errorf("se esperaba un verbo, se encontró %s (%s)", p.curToken.Type, p.curToken.Literal)
+		sugerencias := p.sugerir(p.curToken.Literal, lexer.VERBO)
+		if p.curToken.Type == lexer.PALABRA && p.corregir(sugerencias) {
+			verbo := p.nuevoVerbo(sugerencias[0].Word)
+			p.nextToken()
+			return verbo, nil
+		}
+
+		err := p.errorEn(p.curToken, CodigoVerboInvalido, esperaVerbo,
+			"se esperaba un verbo, se encontró %s (%s)", p.curToken.Type, p.curToken.Literal)
+		err.Suggestions = sugerencias
+		return nil, err
 	}
 
-	verbo := &ast.Verbo{Value: p.curToken.Literal}
+	verbo := p.nuevoVerbo(p.curToken.Keyword)
 	p.nextToken()
 	return verbo, nil
 }
 
-// parseDetalle analiza un detalle (tipo evento + nombre o texto genérico)
-func (p *Parser) parseDetalle() (*ast.DetalleEvento, error) {
-	detalle := &ast.DetalleEvento{}
+// nuevoVerbo crea el nodo de un verbo con el tipo de acción que le asigna el vocabulario
+func (p *Parser) nuevoVerbo(palabra string) *ast.Verbo {
+	tipo, _ := p.l.Vocabulary().VerbType(palabra)
+	return &ast.Verbo{Value: palabra, Tipo: tipo}
+}
 
+// parseDetalle analiza la regla
+//
+//	DETALLE → [ TIPOEVENTO [ ( "con" | "de" ) NOMBRE ] ]
+//	          { PALABRA | TEXTO | PARTICIPANTES | LUGAR | TEMA | MARCA }
+//
+// y agrega lo que lee al detalle, que puede venir de partes anteriores de la
+// oración cortadas por el tiempo. El tipo de evento sólo se reconoce al
+// comienzo de la descripción. Los participantes, el lugar y el tema se
+// guardan aparte y también forman parte de la descripción (Palabras). El
+// texto entre comillas se toma tal cual, sin las comillas.
+func (p *Parser) parseDetalle(detalle *ast.DetalleEvento) *AnalyzerError {
 	// Verificamos si hay un tipo de evento
-	if p.curToken.Type == lexer.TIPOEVENTO {
-		detalle.TipoEvento = p.curToken.Literal
+	if len(detalle.Palabras) == 0 && p.curToken.Type == lexer.TIPOEVENTO {
+		detalle.TipoEvento = p.curToken.Keyword
+		detalle.Palabras = append(detalle.Palabras, p.curToken.Literal)
 		p.nextToken()
+		for p.esMarca(p.curToken) {
+			p.parseMarca(detalle) // "reunión importante con Ana"
+		}
 
-		// Verificamos si es seguido por "con" o "de"
-		if p.curToken.Type == lexer.CON || p.curToken.Type == lexer.DE {
+		// Verificamos si es seguido por "con" o "de" y un nombre
+		switch {
+		case p.esInicioParticipantes():
+			desde, primero := p.curToken, p.peekToken
+			detalle.Participantes = p.parseParticipantes()
+			detalle.Nombre = p.l.Input()[primero.Pos:p.anterior().End]
+			detalle.Palabras = p.agregarPalabras(detalle.Palabras, desde, p.anterior())
+		case p.curToken.Type == lexer.DE && p.peekToken.Type == lexer.PALABRA:
+			detalle.Palabras = append(detalle.Palabras, p.curToken.Literal)
 			p.nextToken()
 
 			// Debe seguir un nombre (una o más palabras)
-			nombre := ""
+			var nombre []string
 			for p.curToken.Type == lexer.PALABRA {
-				nombre += p.curToken.Literal + " "
+				nombre = append(nombre, p.curToken.Literal)
+				detalle.Palabras = append(detalle.Palabras, p.curToken.Literal)
 				p.nextToken()
 			}
-			detalle.Nombre = nombre
+			detalle.Nombre = strings.Join(nombre, " ")
 		}
 	}
 
-	// Verificamos si hay texto genérico
-	texto := ""
-	for p.curToken.Type == lexer.PALABRA {
-		texto += p.curToken.Literal + " "
-		p.nextToken()
+	// Texto genérico, participantes, lugar y tema, en cualquier orden
+	var texto []string
+	defer func() {
+		if len(texto) > 0 {
+			detalle.Texto = strings.TrimSpace(detalle.Texto + " " + strings.Join(texto, " "))
+		}
+	}()
+	for {
+		desde := p.curToken
+		switch {
+		case p.esFinComando():
+			return nil
+		case p.esMarca(p.curToken):
+			p.parseMarca(detalle)
+			continue
+		case esComillaSinCerrar(p.curToken):
+			err := p.errorEn(p.curToken, CodigoSintaxis, nil, "falta cerrar las comillas: %s", p.curToken.Literal)
+			p.nextToken()
+			return err
+		case p.esInicioParticipantes():
+			detalle.Participantes = append(detalle.Participantes, p.parseParticipantes()...)
+		case detalle.Lugar == "" && p.esInicioLugar():
+			detalle.Lugar = p.parseLugar()
+		case detalle.Tema == "" && p.esInicioTema():
+			detalle.Tema = p.parseTema()
+		case p.esPalabraDetalle() && !p.corregirFechaFinal():
+			texto = p.agregarPalabra(texto, p.curToken)
+			detalle.Palabras = p.agregarPalabra(detalle.Palabras, p.curToken)
+			p.nextToken()
+			continue
+		default:
+			return nil
+		}
+		detalle.Palabras = p.agregarPalabras(detalle.Palabras, desde, p.anterior())
 	}
-	detalle.Texto = texto
+}
+
+// esInicioDetalle indica si el token actual comienza o continúa el detalle
+func (p *Parser) esInicioDetalle() bool {
+	return esComillaSinCerrar(p.curToken) || p.esMarca(p.curToken) || p.esInicioParticipantes() || p.esInicioLugar() ||
+		p.esInicioTema() || p.esPalabraDetalle()
+}
 
-	// Si no hay tipo de evento ni texto, es un error
-	if detalle.TipoEvento == "" && detalle.Texto == "" {
-		return nil, fmt.Errorf("se esperaba un detalle de evento o texto, se encontró %s", p.curToken.Type)
+// esPalabraDetalle indica si el token actual forma parte de la descripción.
+// "a las" / "a la" sólo comienzan una hora si les sigue un número, una
+// fracción o el fin del comando; en "llamar a la abuela" son parte del
+// texto. Del mismo modo "el", "en" y "este" sólo comienzan una fecha si les
+// sigue lo que la gramática espera ("el 15", "en 3 días", "este viernes"),
+// "cada" sólo comienza una recurrencia si le sigue una unidad o un día
+// ("cada lunes", pero "cada uno trae algo"), "de" y "desde" un rango si les
+// sigue una hora y el fin ("de 10 a 12"), "durante" y "por" una duración si
+// les sigue una cantidad de tiempo ("por 2 horas", pero "pasar por la
+// farmacia"), los números son texto salvo que comiencen una fecha o parezcan
+// una hora ("comprar 2 kilos", pero "15 de mayo" y "10:30"), la coma sólo si
+// le sigue otra palabra ("pan, leche"), los demás signos siempre ("PR #42")
+// y una palabra de prioridad sólo si está pegada a la anterior
+// ("reunión-importante").
+func (p *Parser) esPalabraDetalle() bool {
+	switch p.curToken.Type {
+	case lexer.PALABRA, lexer.TIPOEVENTO, lexer.CON, lexer.PERIODO, lexer.Y, lexer.MENOS,
+		lexer.FRACCION, lexer.UNIDAD, lexer.HASTA, lexer.DURACION, lexer.SOBRE, lexer.TEXTO:
+		return true
+	case lexer.COMA:
+		return esPalabra(p.peekToken)
+	case lexer.DE:
+		return !p.esInicioRango()
+	case lexer.DESDE:
+		return !p.esInicioRango() && !p.esInicioFecha()
+	case lexer.DURANTE:
+		return !p.esInicioDuracion()
+	case lexer.ALAS:
+		return p.peekToken.Type != lexer.NUMERO && p.peekToken.Type != lexer.FRACCION &&
+			p.peekToken.Type != lexer.EOF
+	case lexer.EL, lexer.EN, lexer.MODIFICADOR:
+		return !p.esInicioFecha()
+	case lexer.NUMERO:
+		return !p.esInicioFecha() && !p.pareceHora()
+	case lexer.SIMBOLO, lexer.GUION, lexer.BARRA, lexer.COLON:
+		return true
+	case lexer.PRIORIDAD:
+		return !p.esMarca(p.curToken)
+	case lexer.CADA:
+		return !p.esInicioRecurrencia()
 	}
+	return false
+}
 
-	return detalle, nil
+// esNumeroEscrito indica si el token es un número escrito con palabras
+func esNumeroEscrito(tok lexer.Token) bool {
+	return tok.Type == lexer.NUMERO && tok.Keyword != ""
 }
 
-// parseTiempo analiza una expresión de tiempo (fecha y/u hora)
-func (p *Parser) parseTiempo() (*ast.Tiempo, error) {
-	tiempo := &ast.Tiempo{}
+// valorNumero devuelve el valor de un token NUMERO, escrito con dígitos
+// ("15") o con palabras ("quince"), cuyo valor guarda el lexer en Keyword
+func valorNumero(tok lexer.Token) int {
+	valor := tok.Literal
+	if tok.Keyword != "" {
+		valor = tok.Keyword
+	}
+	n, _ := strconv.Atoi(valor)
+	return n
+}
+
+// partesTiempo registra qué partes del tiempo ya se analizaron
+type partesTiempo struct {
+	fecha, hora, duracion, recurrencia bool
+}
 
-	// El tiempo puede ser fecha, hora, ambos o ninguno (epsilon)
+// esInicioTiempo indica si el token actual comienza una parte del tiempo:
+// una fecha, una hora o rango de horas, una duración o una recurrencia
+func (p *Parser) esInicioTiempo() bool {
+	return p.esInicioFecha() || p.esInicioHora() || p.esInicioRango() ||
+		p.esInicioDuracion() || p.esInicioRecurrencia()
+}
+
+// enSiguiente evalúa f con el parser en el token siguiente, sin avanzar
+func (p *Parser) enSiguiente(f func() bool) bool {
+	p.nextToken()
+	defer p.retroceder()
+	return f()
+}
+
+// retroceder vuelve al token anterior
+func (p *Parser) retroceder() {
+	p.position -= 2
+	p.nextToken()
+}
 
-	// Primero intentamos parsear una fecha
-	fecha, err := p.parseFecha()
-	if err == nil {
+// parseParteTiempo analiza una parte de la regla
+//
+//	TIEMPO → FECHA | RANGO_HORA | DURACION | RECURRENCIA
+//
+// y la agrega a tiempo. Cada parte puede aparecer una sola vez en el comando:
+// una segunda fecha ("mañana ... el lunes"), hora, duración o recurrencia es
+// un error TIME_CONFLICT. Los errores se registran y el análisis continúa
+// desde el siguiente punto de sincronización.
+func (p *Parser) parseParteTiempo(tiempo *ast.Tiempo, vistas *partesTiempo) {
+	desde := p.curToken
+	switch {
+	case p.esInicioFecha():
+		// La fecha se valida sin "desde" ni "el"
+		n := 0
+		if p.tokenEn(n).Type == lexer.DESDE {
+			n++
+		}
+		if p.tokenEn(n).Type == lexer.EL {
+			n++
+		}
+		desde = p.tokenEn(n)
+		fecha, err := p.parseFecha()
+		if err != nil {
+			p.addError(err)
+			p.sincronizar()
+			return
+		}
+		if vistas.fecha {
+			p.addError(p.errorEntre(desde, p.anterior(), CodigoConflictoTiempo, nil,
+				"ya se indicó la fecha (%s), se encontró otra: %s", tiempo.Fecha.String(), fecha.String()))
+			return
+		}
+		vistas.fecha = true
+		if fecha.Tipo == "especifica" {
+			p.validarFecha(fecha, desde, p.anterior())
+		}
 		tiempo.Fecha = fecha
+	case p.esInicioHora() || p.esInicioRango():
+		hora, fin, err := p.parseRangoHora()
+		if err != nil {
+			p.addError(err)
+			p.sincronizar()
+			return
+		}
+		if vistas.hora {
+			p.addError(p.errorEntre(desde, p.anterior(), CodigoConflictoTiempo, nil,
+				"ya se indicó la hora (%s), se encontró otra: %s", tiempo.Hora.String(), hora.String()))
+			return
+		}
+		vistas.hora = true
+		tiempo.Hora, tiempo.HoraFin = hora, fin
+		if fin != nil && tiempo.Duracion != nil {
+			p.addError(p.errorEntre(desde, p.anterior(), CodigoDuracionInvalida, nil,
+				"no se puede indicar la hora de fin y la duración a la vez"))
+		}
+	case p.esInicioDuracion():
+		duracion, err := p.parseDuracion()
+		if err != nil {
+			p.addError(err)
+			p.sincronizar()
+			return
+		}
+		if vistas.duracion {
+			p.addError(p.errorEntre(desde, p.anterior(), CodigoConflictoTiempo, nil,
+				"ya se indicó la duración (%s), se encontró otra: %s", tiempo.Duracion.String(), duracion.String()))
+			return
+		}
+		vistas.duracion = true
+		tiempo.Duracion = duracion
+		if tiempo.HoraFin != nil {
+			p.addError(p.errorEntre(desde, p.anterior(), CodigoDuracionInvalida, nil,
+				"no se puede indicar la hora de fin y la duración a la vez"))
+		}
+	case p.esInicioRecurrencia():
+		recurrencia, err := p.parseRecurrencia()
+		if err != nil {
+			p.addError(err)
+			p.sincronizar()
+			return
+		}
+		if vistas.recurrencia {
+			p.addError(p.errorEntre(desde, p.anterior(), CodigoConflictoTiempo, nil,
+				"ya se indicó la repetición (%s), se encontró otra: %s", tiempo.Recurrencia.String(), recurrencia.String()))
+			return
+		}
+		vistas.recurrencia = true
+		tiempo.Recurrencia = recurrencia
 	}
+}
 
-	// Luego intentamos parsear una hora
-	hora, err := p.parseHora()
-	if err == nil {
-		tiempo.Hora = hora
+// sincronizar descarta tokens hasta llegar a uno desde el que se pueda
+// retomar el análisis: el comienzo de una parte del tiempo, una palabra de
+// la descripción o EOF. Los números y signos no retoman la descripción, así
+// no se leen como texto los restos de una fecha u hora inválida. Siempre
+// avanza al menos un token y devuelve el último token descartado.
+func (p *Parser) sincronizar() lexer.Token {
+	ultimo := p.curToken
+	p.nextToken()
+	for p.curToken.Type != lexer.EOF && !p.esInicioTiempo() && !esPalabra(p.curToken) && !p.esMarca(p.curToken) &&
+		!p.esFinComando() {
+		ultimo = p.curToken
+		p.nextToken()
 	}
+	return ultimo
+}
 
-	// Si no hay ni fecha ni hora, es epsilon (válido)
-	if tiempo.Fecha == nil && tiempo.Hora == nil {
-		return tiempo, nil
+// literales devuelve los literales de los tokens entre desde y hasta (inclusive)
+func (p *Parser) literales(desde, hasta lexer.Token) []string {
+	var literales []string
+	for _, tok := range p.tokens {
+		if tok.Pos >= desde.Pos && tok.End <= hasta.End && tok.Type != lexer.EOF {
+			literales = append(literales, tok.Literal)
+		}
 	}
+	return literales
+}
 
-	return tiempo, nil
+// esInicioHora indica si el token actual puede comenzar una hora. En inglés
+// "at" también comienza el lugar ("at the office").
+func (p *Parser) esInicioHora() bool {
+	return (p.curToken.Type == lexer.ALAS && !p.esInicioLugar()) || p.curToken.Type == lexer.HORAFIJA
 }
 
-// parseFecha analiza una expresión de fecha
-func (p *Parser) parseFecha() (*ast.Fecha, error) {
+// esInicioFecha indica si el token actual puede comenzar una fecha
+func (p *Parser) esInicioFecha() bool {
+	switch p.curToken.Type {
+	case lexer.FECHARELATIVA, lexer.DIASEMANA, lexer.MES, lexer.FINPERIODO:
+		return true
+	case lexer.NUMERO:
+		if esNumeroEscrito(p.curToken) {
+			return p.sigueMes(1)
+		}
+		return p.esFechaNumerica()
+	case lexer.EL:
+		if esNumeroEscrito(p.peekToken) {
+			return p.sigueMes(2) || p.terminaFecha(2)
+		}
+		return sigueFecha(p.peekToken.Type)
+	case lexer.MODIFICADOR:
+		return p.peekToken.Type == lexer.DIASEMANA
+	case lexer.EN:
+		return p.peekToken.Type == lexer.NUMERO && p.tokenEn(2).Type == lexer.UNIDAD
+	case lexer.DESDE:
+		// "cada lunes desde el 5 de mayo": desde cuándo se repite
+		return p.peekToken.Type != lexer.DESDE && p.enSiguiente(p.esInicioFecha)
+	}
+	return false
+}
+
+// sigueMes indica si el token n posiciones después del actual es un mes,
+// con o sin "de" antes. Un número escrito con palabras sólo comienza una
+// fecha si le sigue el mes ("el cinco de mayo") o, tras "el", la hora o el
+// fin del comando ("el quince"); si no es parte de la descripción ("comprar
+// dos kilos").
+func (p *Parser) sigueMes(n int) bool {
+	if p.tokenEn(n).Type == lexer.DE {
+		n++
+	}
+	return p.tokenEn(n).Type == lexer.MES
+}
+
+// esFechaNumerica indica si el número actual, escrito con dígitos, comienza
+// una fecha: le sigue el mes ("15 de mayo"), una palabra parecida a un mes
+// ("15 de mayp", que se informa como un mes mal escrito), o es una fecha con
+// barras o guiones ("15/03", "2025-03-15"). Si no, es parte de la
+// descripción ("comprar 2 kilos", "llamar al 0800"). Seguida de una unidad de
+// medida, una fecha con barras es una cantidad ("comprar 1/2 kilo"); después
+// de "el" siempre es una fecha, porque no se pasa por aquí.
+func (p *Parser) esFechaNumerica() bool {
+	switch p.peekToken.Type {
+	case lexer.BARRA:
+		if unidad := p.tokenEn(3); unidad.Type == lexer.PALABRA && p.l.Vocabulary().IsMeasure(unidad.Literal) {
+			return false
+		}
+		return len(p.curToken.Literal) <= 2 && esNumeroCorto(p.tokenEn(2))
+	case lexer.GUION:
+		// Un teléfono ("0800-333-1234") no es una fecha ISO
+		return len(p.curToken.Literal) == 4 && esNumeroCorto(p.tokenEn(2)) &&
+			p.tokenEn(3).Type == lexer.GUION && esNumeroCorto(p.tokenEn(4))
+	}
+	n := 1
+	if p.tokenEn(n).Type == lexer.DE {
+		n++
+	}
+	tok := p.tokenEn(n)
+	return tok.Type == lexer.MES || (tok.Type == lexer.PALABRA && len(p.sugerir(tok.Literal, lexer.MES)) > 0)
+}
+
+// esNumeroCorto indica si el token es un número de uno o dos dígitos, como
+// el día o el mes de una fecha numérica
+func esNumeroCorto(tok lexer.Token) bool {
+	return tok.Type == lexer.NUMERO && !esNumeroEscrito(tok) && len(tok.Literal) <= 2
+}
+
+// pareceHora indica si el número actual, escrito con dígitos, es una hora
+// sin "a las" ("10:30", "5 pm"). No es parte de la descripción, así se
+// informa en lugar de guardarse como texto; "2 horas" sí es texto.
+func (p *Parser) pareceHora() bool {
+	if esNumeroEscrito(p.curToken) {
+		return false
+	}
+	switch p.peekToken.Type {
+	case lexer.COLON:
+		return true
+	case lexer.PERIODO:
+		_, esDuracion := p.l.Vocabulary().DurationMinutes(p.peekToken.Keyword)
+		return !esDuracion
+	}
+	return false
+}
+
+// terminaFecha indica si el token n posiciones después del actual puede
+// seguir a una fecha de sólo el día ("el quince a las diez")
+func (p *Parser) terminaFecha(n int) bool {
+	switch p.tokenEn(n).Type {
+	case lexer.EOF, lexer.ALAS, lexer.HORAFIJA:
+		return true
+	}
+	return false
+}
+
+// sigueFecha indica si un token de ese tipo puede seguir a "el" en una fecha
+func sigueFecha(tipo lexer.TokenType) bool {
+	switch tipo {
+	case lexer.DIASEMANA, lexer.NUMERO, lexer.MES, lexer.MODIFICADOR:
+		return true
+	}
+	return false
+}
+
+// parseFecha analiza la regla
+//
+//	FECHA → [ "el" ] ( FECHA_RELATIVA | FIN_PERIODO
+//	        | "en" NUMERO UNIDAD
+//	        | [ MODIFICADOR ] DIA_SEMANA [ MODIFICADOR ]
+//	        | NUMERO [ "de" ] MES [ [ "de" ] AÑO ]
+//	        | NUMERO "/" NUMERO [ "/" AÑO ]
+//	        | AÑO "-" NUMERO "-" NUMERO
+//	        | "el" NUMERO
+//	        | MES NUMERO [ AÑO ] )
+//
+// La forma con el mes primero es la habitual en inglés ("March 15 2025").
+// El año es opcional; si falta, al transformar se usa la próxima vez que
+// ocurre la fecha, y en "el 15" también el mes.
+// Las fechas relativas se guardan como un desplazamiento desde la fecha de
+// referencia, que se resuelve al transformar el comando.
+func (p *Parser) parseFecha() (*ast.Fecha, *AnalyzerError) {
 	fecha := &ast.Fecha{}
+	vocabulario := p.l.Vocabulary()
+
+	// Puede empezar con "desde" ("cada lunes desde el 5 de mayo")
+	if p.curToken.Type == lexer.DESDE {
+		p.nextToken()
+	}
+	desde := p.curToken
+
+	// Puede empezar con "el" ("el lunes", "on Monday")
+	if p.curToken.Type == lexer.EL {
+		p.nextToken()
+	}
 
-	// Puede ser una fecha relativa
+	// Puede ser una fecha relativa (hoy, pasado mañana, la semana que viene)
 	if p.curToken.Type == lexer.FECHARELATIVA {
 		fecha.Tipo = "relativa"
-		fecha.Valor = p.curToken.Literal
+		fecha.Valor = p.curToken.Keyword
+		cantidad, unidad, _ := vocabulario.RelativeOffset(p.curToken.Keyword)
+		fecha.Desplazamiento = &ast.Desplazamiento{Cantidad: cantidad, Unidad: unidad}
+		p.nextToken()
+		return fecha, nil
+	}
+
+	// Puede ser el fin de un periodo (fin de mes, fin de semana)
+	if p.curToken.Type == lexer.FINPERIODO {
+		fecha.Tipo = "fin"
+		fecha.Valor = p.curToken.Keyword
+		unidad, _ := vocabulario.PeriodEndOf(p.curToken.Keyword)
+		fecha.Fin = &ast.FinDePeriodo{Unidad: unidad}
+		p.nextToken()
+		return fecha, nil
+	}
+
+	// Puede ser un desplazamiento (en 3 días, dentro de 2 semanas). Sólo se
+	// llega aquí si esInicioFecha vio NUMERO y UNIDAD después de "en".
+	if p.curToken.Type == lexer.EN {
+		p.nextToken()
+		cantidad := valorNumero(p.curToken)
+		p.nextToken()
+		unidad, _ := vocabulario.UnitOf(p.curToken.Keyword)
+		fecha.Tipo = "desplazamiento"
+		fecha.Valor = p.l.Input()[desde.Pos:p.curToken.End]
+		fecha.Desplazamiento = &ast.Desplazamiento{Cantidad: cantidad, Unidad: unidad}
 		p.nextToken()
 		return fecha, nil
 	}
 
-	// Puede ser un día de la semana
+	// Puede haber un modificador antes del día (el próximo viernes, este viernes)
+	modificador := ""
+	if p.curToken.Type == lexer.MODIFICADOR {
+		modificador, _ = vocabulario.ModifierOf(p.curToken.Keyword)
+		p.nextToken()
+		if p.curToken.Type != lexer.DIASEMANA {
+			return nil, p.errorEn(p.curToken, CodigoFechaInvalida, esperaDiaSemana,
+				"se esperaba un día de la semana, se encontró %s", p.curToken.Type)
+		}
+	}
+
+	// Puede ser un día de la semana, con el modificador antes o después
+	// ("el viernes que viene")
 	if p.curToken.Type == lexer.DIASEMANA {
 		fecha.Tipo = "diasemana"
-		fecha.Valor = p.curToken.Literal
+		fecha.Valor = p.curToken.Keyword
+		fecha.Dia = p.curToken.Keyword
 		p.nextToken()
+
+		if modificador == "" && p.curToken.Type == lexer.MODIFICADOR {
+			modificador, _ = vocabulario.ModifierOf(p.curToken.Keyword)
+			p.nextToken()
+		}
+		if modificador != "" {
+			fecha.Modificador = modificador
+			fecha.Valor = p.l.Input()[desde.Pos:p.anterior().End]
+		}
 		return fecha, nil
 	}
 
-	// Puede ser una fecha específica (12 de mayo)
-	if p.curToken.Type == lexer.NUMERO {
+	// Puede ser una fecha específica con el mes primero (March 15)
+	if p.curToken.Type == lexer.MES {
 		fecha.Tipo = "especifica"
-		num, _ := strconv.Atoi(p.curToken.Literal)
-		fecha.Numero = num
+		fecha.Mes = p.curToken.Keyword
 		p.nextToken()
 
-		// Debe seguir "de"
-		if p.curToken.Type != lexer.DE {
-			return nil, fmt.Errorf("se esperaba 'de' después del número, se encontró %s", p.curToken.Type)
+		if p.curToken.Type != lexer.NUMERO {
+			return nil, p.errorEn(p.curToken, CodigoFechaInvalida, esperaNumero,
+				"se esperaba un día después del mes, se encontró %s", p.curToken.Type)
+		}
+		fecha.Numero = p.parseDia()
+		p.parseAnio(fecha)
+		return fecha, nil
+	}
+
+	// Puede ser una fecha específica (12 de mayo, 15/03, 2025-03-15, el 15)
+	if p.curToken.Type == lexer.NUMERO {
+		fecha.Tipo = "especifica"
+
+		switch p.peekToken.Type {
+		case lexer.GUION:
+			return p.parseFechaISO(fecha)
+		case lexer.BARRA:
+			return p.parseFechaBarras(fecha)
+		}
+
+		fecha.Numero = p.parseDia()
+
+		// Sólo el día ("el 15"): el mes se deduce al transformar el comando
+		if desde.Type == lexer.EL && p.curToken.Type != lexer.DE &&
+			p.curToken.Type != lexer.MES && p.curToken.Type != lexer.PALABRA {
+			return fecha, nil
+		}
+
+		// Puede seguir "de" antes del mes
+		if p.curToken.Type == lexer.DE {
+			p.nextToken()
+		} else if p.curToken.Type != lexer.MES && p.curToken.Type != lexer.PALABRA {
+			return nil, p.errorEn(p.curToken, CodigoSintaxis, esperaDeOMes,
+				"se esperaba un mes después del número, se encontró %s", p.curToken.Type)
 		}
-		p.nextToken()
 
 		// Debe seguir un mes
 		if p.curToken.Type != lexer.MES {
-			return nil, fmt.Errorf("se esperaba un mes, se encontró %s", p.curToken.Type)
+			sugerencias := p.sugerir(p.curToken.Literal, lexer.MES)
+			if p.curToken.Type != lexer.PALABRA || !p.corregir(sugerencias) {
+				err := p.errorEn(p.curToken, CodigoFechaInvalida, esperaMes,
+					"se esperaba un mes, se encontró %s (%s)", p.curToken.Type, p.curToken.Literal)
+				err.Suggestions = sugerencias
+				return nil, err
+			}
+			p.curToken.Keyword = sugerencias[0].Word
 		}
-		fecha.Mes = p.curToken.Literal
+		fecha.Mes = p.curToken.Keyword
 		p.nextToken()
 
-		// Opcionalmente puede seguir un año
+		// Opcionalmente puede seguir un año, con o sin "de"
 		if p.curToken.Type == lexer.DE && p.peekToken.Type == lexer.NUMERO {
 			p.nextToken() // Saltamos "de"
-			anio, _ := strconv.Atoi(p.curToken.Literal)
-			fecha.Anio = anio
-			p.nextToken()
 		}
+		p.parseAnio(fecha)
 
 		return fecha, nil
 	}
 
-	return nil, fmt.Errorf("se esperaba una fecha, se encontró %s", p.curToken.Type)
+	return nil, p.errorEn(p.curToken, CodigoSintaxis, esperaInicioFecha,
+		"se esperaba una fecha, se encontró %s", p.curToken.Type)
+}
+
+// parseDia lee el número de día de una fecha específica. El día se valida
+// junto con el mes y el año en validarFecha.
+func (p *Parser) parseDia() int {
+	dia := valorNumero(p.curToken)
+	p.nextToken()
+	return dia
+}
+
+// parseMesNumero lee el número de mes de una fecha numérica
+func (p *Parser) parseMesNumero() int {
+	mes := valorNumero(p.curToken)
+	if mes < 1 || mes > 12 {
+		p.addError(p.errorEn(p.curToken, CodigoFechaInvalida, nil, "mes fuera de rango: %d", mes))
+	}
+	p.nextToken()
+	return mes
+}
+
+// parseFechaBarras analiza NUMERO "/" NUMERO [ "/" AÑO ]. El vocabulario
+// define si el día va primero ("15/03") o el mes ("03/15" en inglés).
+func (p *Parser) parseFechaBarras(fecha *ast.Fecha) (*ast.Fecha, *AnalyzerError) {
+	mesPrimero := p.l.Vocabulary().MonthFirst()
+	if mesPrimero {
+		fecha.MesNumero = p.parseMesNumero()
+	} else {
+		fecha.Numero = p.parseDia()
+	}
+	p.nextToken() // Saltamos "/"
+
+	if p.curToken.Type != lexer.NUMERO {
+		return nil, p.errorEn(p.curToken, CodigoFechaInvalida, esperaNumero,
+			"se esperaba un número en la fecha, se encontró %s", p.curToken.Type)
+	}
+	if mesPrimero {
+		fecha.Numero = p.parseDia()
+	} else {
+		fecha.MesNumero = p.parseMesNumero()
+	}
+
+	// Opcionalmente puede seguir "/" y el año
+	if p.curToken.Type == lexer.BARRA {
+		p.nextToken()
+		if p.curToken.Type != lexer.NUMERO {
+			return nil, p.errorEn(p.curToken, CodigoFechaInvalida, esperaNumero,
+				"se esperaba un número en la fecha, se encontró %s", p.curToken.Type)
+		}
+		p.parseAnio(fecha)
+	}
+	return fecha, nil
+}
+
+// parseFechaISO analiza AÑO "-" NUMERO "-" NUMERO ("2025-03-15")
+func (p *Parser) parseFechaISO(fecha *ast.Fecha) (*ast.Fecha, *AnalyzerError) {
+	p.parseAnio(fecha)
+
+	for i := 0; i < 2; i++ {
+		if p.curToken.Type != lexer.GUION {
+			return nil, p.errorEn(p.curToken, CodigoFechaInvalida, []string{lexer.GUION},
+				"se esperaba '-' en la fecha, se encontró %s", p.curToken.Type)
+		}
+		p.nextToken()
+		if p.curToken.Type != lexer.NUMERO {
+			return nil, p.errorEn(p.curToken, CodigoFechaInvalida, esperaNumero,
+				"se esperaba un número en la fecha, se encontró %s", p.curToken.Type)
+		}
+		if i == 0 {
+			fecha.MesNumero = p.parseMesNumero()
+		} else {
+			fecha.Numero = p.parseDia()
+		}
+	}
+	return fecha, nil
 }
 
-// parseHora analiza una expresión de hora
-func (p *Parser) parseHora() (*ast.Hora, error) {
+// parseAnio lee el año opcional de una fecha específica y comprueba que esté
+// dentro del rango aceptado
+func (p *Parser) parseAnio(fecha *ast.Fecha) {
+	if p.curToken.Type != lexer.NUMERO {
+		return
+	}
+	fecha.Anio = valorNumero(p.curToken)
+	if !anioValido(fecha.Anio) {
+		p.addError(p.errorEn(p.curToken, CodigoFechaInvalida, nil,
+			"año fuera de rango: %d (debe estar entre %d y %d)", fecha.Anio, anioMinimo, anioMaximo))
+	}
+	p.nextToken()
+}
+
+// parseHora analiza la regla
+//
+//	HORA → HORA_FIJA
+//	     | "a las" NUMERO [ ":" MINUTOS | ( "y" | "menos" ) ( NUMERO | FRACCION ) | NUMERO ] [ PERIODO ]
+//	     | "a las" FRACCION ( "y" | "menos" ) NUMERO [ PERIODO ]
+//
+// La última forma es la del inglés ("at half past 5", "at quarter to 6").
+// Los números pueden escribirse con palabras ("a las diez y cuarto"); en ese
+// caso los minutos también pueden seguir directamente a la hora ("a las once
+// quince").
+func (p *Parser) parseHora() (*ast.Hora, *AnalyzerError) {
 	hora := &ast.Hora{}
 
+	// Puede ser una hora fija (al mediodía, a la medianoche)
+	if p.curToken.Type == lexer.HORAFIJA {
+		hora.Hora, hora.Minutos, _ = p.l.Vocabulary().FixedTimeOf(p.curToken.Keyword)
+		hora.Nombre = p.curToken.Keyword
+		p.nextToken()
+		return hora, nil
+	}
+
 	// Debe comenzar con "a las"
 	if p.curToken.Type != lexer.ALAS {
-		return nil, fmt.Errorf("se esperaba 'a las', se encontró %s", p.curToken.Type)
+		return nil, p.errorEn(p.curToken, CodigoSintaxis, []string{lexer.ALAS},
+			"se esperaba 'a las', se encontró %s", p.curToken.Type)
 	}
 	p.nextToken()
 
+	if err := p.parseValorHora(hora, false); err != nil {
+		return nil, err
+	}
+	return hora, nil
+}
+
+// parseValorHora analiza la hora que sigue a "a las" (o a "de", "hasta" en
+// un rango) y completa el nodo. Al comienzo de un rango "menos" seguido de un
+// número separa las horas ("from 2 to 4") en lugar de restar minutos.
+func (p *Parser) parseValorHora(hora *ast.Hora, inicioRango bool) *AnalyzerError {
+	// Fracción antes de la hora: "half past 5", "quarter to 6"
+	fraccionPrevia := false
+	if p.curToken.Type == lexer.FRACCION && (p.peekToken.Type == lexer.Y || p.peekToken.Type == lexer.MENOS) {
+		minutos, _ := p.l.Vocabulary().FractionMinutes(p.curToken.Keyword)
+		p.nextToken()
+		if p.curToken.Type == lexer.MENOS {
+			hora.Menos = minutos
+		} else {
+			hora.Minutos = minutos
+		}
+		p.nextToken()
+		fraccionPrevia = true
+	}
+
 	// Debe seguir un número
 	if p.curToken.Type != lexer.NUMERO {
-		return nil, fmt.Errorf("se esperaba un número, se encontró %s", p.curToken.Type)
+		return p.errorEn(p.curToken, CodigoHoraInvalida, esperaNumero,
+			"se esperaba hora después de 'a las', se encontró %s", p.curToken.Type)
 	}
 
 	// Parseamos la hora
-	horaVal, _ := strconv.Atoi(p.curToken.Literal)
+	tokenHora := p.curToken
+	horaVal := valorNumero(p.curToken)
+	if horaVal < 0 || horaVal > 23 {
+		p.addError(p.errorEn(p.curToken, CodigoHoraInvalida, nil, "hora fuera de rango: %d", horaVal))
+	}
 	hora.Hora = horaVal
 	p.nextToken()
 
-	// Puede haber minutos (después de :)
-	if p.curToken.Type == lexer.COLON {
+	switch {
+	case fraccionPrevia:
+		// Los minutos ya se leyeron antes de la hora
+
+	case p.curToken.Type == lexer.COLON:
+		// Puede haber minutos (después de :)
 		p.nextToken()
 		if p.curToken.Type != lexer.NUMERO {
-			return nil, fmt.Errorf("se esperaba un número para los minutos, se encontró %s", p.curToken.Type)
+			return p.errorEn(p.curToken, CodigoHoraInvalida, esperaNumero,
+				"se esperaba un número para los minutos, se encontró %s", p.curToken.Type)
+		}
+		minutos := valorNumero(p.curToken)
+		if len(p.curToken.Literal) != 2 {
+			p.addError(p.errorEn(p.curToken, CodigoHoraInvalida, nil,
+				"minutos deben tener 2 dígitos: '%s'", p.curToken.Literal))
+		} else if minutos > 59 {
+			p.addError(p.errorEn(p.curToken, CodigoHoraInvalida, nil, "minutos fuera de rango: %d", minutos))
+		}
+		hora.Minutos = minutos
+		p.nextToken()
+
+	case (p.curToken.Type == lexer.Y || p.curToken.Type == lexer.MENOS) &&
+		(p.peekToken.Type == lexer.NUMERO || p.peekToken.Type == lexer.FRACCION) &&
+		!(inicioRango && p.curToken.Type == lexer.MENOS && p.peekToken.Type == lexer.NUMERO):
+		// Minutos coloquiales: "5 y media", "6 menos cuarto", "8 y 10"
+		menos := p.curToken.Type == lexer.MENOS
+		p.nextToken()
+
+		var minutos int
+		if p.curToken.Type == lexer.FRACCION {
+			minutos, _ = p.l.Vocabulary().FractionMinutes(p.curToken.Keyword)
+		} else {
+			minutos = valorNumero(p.curToken)
+			if minutos < 1 || minutos > 59 {
+				p.addError(p.errorEn(p.curToken, CodigoHoraInvalida, nil, "minutos fuera de rango: %d", minutos))
+			}
+		}
+		if menos {
+			hora.Menos = minutos
+		} else {
+			hora.Minutos = minutos
+		}
+		p.nextToken()
+
+	case esNumeroEscrito(tokenHora) && esNumeroEscrito(p.curToken):
+		// Minutos dictados después de la hora: "a las once quince"
+		minutos := valorNumero(p.curToken)
+		if minutos < 1 || minutos > 59 {
+			p.addError(p.errorEn(p.curToken, CodigoHoraInvalida, nil, "minutos fuera de rango: %d", minutos))
 		}
-		minutos, _ := strconv.Atoi(p.curToken.Literal)
 		hora.Minutos = minutos
 		p.nextToken()
 	}
 
-	// Puede haber un periodo (am/pm/hs)
+	// Puede haber un periodo (am, pm, de la tarde) que debe corresponder a la hora
 	if p.curToken.Type == lexer.PERIODO {
-		hora.Periodo = p.curToken.Literal
+		hora.Periodo = p.curToken.Keyword
+		if _, ok := p.l.Vocabulary().ResolveHour(hora.Periodo, hora.Hora); !ok && hora.Hora <= 23 {
+			p.addError(p.errorEntre(tokenHora, p.curToken, CodigoHoraInvalida, nil,
+				"la hora %d no corresponde a '%s'", hora.Hora, p.curToken.Literal))
+		}
 		p.nextToken()
 	}
 
-	return hora, nil
+	return nil
 }
 
-// Parse analiza la entrada y construye un AST
+// Parse analiza la entrada como un único comando y construye su AST. Si la
+// entrada tiene más de un comando, los siguientes se reportan como tokens
+// inesperados; ParseSentencias los analiza por separado.
 func (p *Parser) Parse() (*ast.Comando, error) {
-	return p.ParseComando()
+	comando, err := p.ParseComando()
+	if p.curToken.Type != lexer.EOF {
+		desde, hasta := p.curToken, p.tokens[len(p.tokens)-2]
+		p.addError(p.errorEntre(desde, hasta, CodigoTokenInesperado, []string{lexer.EOF},
+			"tokens inesperados al final: %v", p.literales(desde, hasta)))
+		for p.curToken.Type != lexer.EOF {
+			p.nextToken()
+		}
+		return nil, p.errors[0]
+	}
+	return comando, err
 }
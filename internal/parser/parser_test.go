@@ -0,0 +1,362 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+)
+
+// nuevoParser crea el parser de la entrada con el vocabulario del idioma
+func nuevoParser(entrada, locale string, opts Options) *Parser {
+	l := lexer.NewWithOptions(entrada, lexer.Options{Vocabulary: vocab.Get(locale)})
+	return NewWithOptions(l, opts)
+}
+
+// parsear analiza la entrada como un único comando en español y devuelve el
+// comando y todos los errores
+func parsear(entrada string, opts Options) (*ast.Comando, []*AnalyzerError) {
+	p := nuevoParser(entrada, "es", opts)
+	comando, _ := p.Parse()
+	return comando, p.Errors()
+}
+
+// partes descompone el comando en sus nodos concretos
+func partes(t *testing.T, comando *ast.Comando) (*ast.Verbo, *ast.DetalleEvento, *ast.Tiempo) {
+	t.Helper()
+	verbo, ok1 := comando.Verbo.(*ast.Verbo)
+	detalle, ok2 := comando.Detalle.(*ast.DetalleEvento)
+	tiempo, ok3 := comando.Tiempo.(*ast.Tiempo)
+	if !ok1 || !ok2 || !ok3 {
+		t.Fatalf("comando incompleto: %+v", comando)
+	}
+	return verbo, detalle, tiempo
+}
+
+// sinErrores analiza la entrada y falla si hubo errores
+func sinErrores(t *testing.T, entrada string, opts Options) (*ast.Verbo, *ast.DetalleEvento, *ast.Tiempo) {
+	t.Helper()
+	comando, errs := parsear(entrada, opts)
+	if len(errs) > 0 {
+		t.Fatalf("%q: errores inesperados: %v", entrada, errs)
+	}
+	return partes(t, comando)
+}
+
+// textoFecha y textoHora escriben los nodos en forma canónica, o "" si faltan
+func textoFecha(f *ast.Fecha) string {
+	if f == nil {
+		return ""
+	}
+	return f.String()
+}
+
+func textoHora(h *ast.Hora) string {
+	if h == nil {
+		return ""
+	}
+	return h.String()
+}
+
+func TestParseComando(t *testing.T) {
+	casos := []struct {
+		entrada     string
+		verbo, tipo string
+		tipoEvento  string
+		palabras    string
+		fecha, hora string
+	}{
+		{"agendá reunión hoy", "agendá", "evento", "reunión", "reunión", "hoy", ""},
+		{"recordame comprar leche mañana a las 10:30", "recordame", "recordatorio", "", "comprar leche", "mañana", "a las 10:30"},
+		{"recordame llamar doctor 15 de marzo 2024", "recordame", "recordatorio", "", "llamar doctor", "15 de marzo 2024", ""},
+		{"agendá cita médica lunes a las 14:00", "agendá", "evento", "cita", "cita médica", "lunes", "a las 14:00"},
+		{"programá cita con Juan 12 de mayo de 2025 a las 15", "programá", "evento", "cita", "cita con Juan", "12 de mayo 2025", "a las 15:00"},
+		{"agendá estudiar para examen", "agendá", "evento", "", "estudiar para examen", "", ""},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			verbo, detalle, tiempo := sinErrores(t, c.entrada, Options{})
+			if verbo.Value != c.verbo || verbo.Tipo != c.tipo {
+				t.Errorf("verbo = %q (%s), se esperaba %q (%s)", verbo.Value, verbo.Tipo, c.verbo, c.tipo)
+			}
+			if detalle.TipoEvento != c.tipoEvento {
+				t.Errorf("tipo de evento = %q, se esperaba %q", detalle.TipoEvento, c.tipoEvento)
+			}
+			if got := strings.Join(detalle.Palabras, " "); got != c.palabras {
+				t.Errorf("palabras = %q, se esperaba %q", got, c.palabras)
+			}
+			if got := textoFecha(tiempo.Fecha); got != c.fecha {
+				t.Errorf("fecha = %q, se esperaba %q", got, c.fecha)
+			}
+			if got := textoHora(tiempo.Hora); got != c.hora {
+				t.Errorf("hora = %q, se esperaba %q", got, c.hora)
+			}
+		})
+	}
+}
+
+func TestParseComandoInvalido(t *testing.T) {
+	casos := []struct {
+		entrada string
+		codigo  string
+	}{
+		{"agendá llamada sábado a las", CodigoHoraInvalida},
+		{"comando inválido", CodigoVerboInvalido},
+		{"agendá", CodigoDetalleFaltante},
+		{"agendá reunión a las 25:00", CodigoHoraInvalida},
+		{"recordame algo a las 15 de la mañana", CodigoHoraInvalida},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			comando, errs := parsear(c.entrada, Options{})
+			if comando != nil {
+				t.Errorf("se esperaba un error, se obtuvo %+v", comando)
+			}
+			if len(errs) == 0 || errs[0].Code != c.codigo {
+				t.Fatalf("errores = %v, se esperaba %s", errs, c.codigo)
+			}
+		})
+	}
+}
+
+func TestPalabrasClaveCompuestas(t *testing.T) {
+	verbo, detalle, tiempo := sinErrores(t, "tengo que pagar la luz pasado mañana a las 9", Options{})
+	if verbo.Value != "tengo que" {
+		t.Errorf("verbo = %q, se esperaba \"tengo que\"", verbo.Value)
+	}
+	if got := strings.Join(detalle.Palabras, " "); got != "pagar la luz" {
+		t.Errorf("palabras = %q", got)
+	}
+	if textoFecha(tiempo.Fecha) != "pasado mañana" || textoHora(tiempo.Hora) != "a las 09:00" {
+		t.Errorf("tiempo = %q %q", textoFecha(tiempo.Fecha), textoHora(tiempo.Hora))
+	}
+}
+
+func TestVocabularioPropio(t *testing.T) {
+	v, err := vocab.Parse([]byte(`{"actionTypes": ["turno"], "verbs": [{"word": "apuntá", "type": "turno"}],
+		"eventTypes": ["consulta"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewWithOptions(lexer.NewWithOptions("apuntá consulta", lexer.Options{Vocabulary: v}), Options{})
+	comando, err := p.Parse()
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	verbo, detalle, _ := partes(t, comando)
+	if verbo.Value != "apuntá" || verbo.Tipo != "turno" || detalle.TipoEvento != "consulta" {
+		t.Errorf("verbo = %+v, tipo de evento = %q", verbo, detalle.TipoEvento)
+	}
+
+	// "agendá" no está en el vocabulario
+	p = NewWithOptions(lexer.NewWithOptions("agendá consulta", lexer.Options{Vocabulary: v}), Options{})
+	if _, err := p.Parse(); err == nil {
+		t.Error("se esperaba un error con un verbo que no está en el vocabulario")
+	}
+}
+
+func TestParseIngles(t *testing.T) {
+	casos := []struct {
+		entrada     string
+		verbo, tipo string
+		palabras    string
+		lugar       string
+		fecha, hora string
+	}{
+		{"schedule meeting tomorrow at 14:30", "schedule", "evento", "meeting", "", "tomorrow", "a las 14:30"},
+		{"remind me call doctor on March 15 2025", "remind me", "recordatorio", "call doctor", "", "15 de march 2025", ""},
+		{"i have to pay rent on friday at 9:00", "i have to", "tarea", "pay rent", "", "friday", "a las 09:00"},
+		{"schedule dentist on 03/15 at 5 pm", "schedule", "evento", "dentist", "", "15/03", "a las 05:00 pm"},
+		{"remind me to call mom at half past 5", "remind me to", "recordatorio", "call mom", "", "", "a las 05:30"},
+		// "at" comienza el lugar antes de un artículo, un nombre propio o comillas
+		{"schedule meeting at the office", "schedule", "evento", "meeting at the office", "the office", "", ""},
+		{"schedule meeting at Starbucks at 5 pm", "schedule", "evento", "meeting at Starbucks", "Starbucks", "", "a las 05:00 pm"},
+		{"schedule meeting at 5 pm at the office", "schedule", "evento", "meeting at the office", "the office", "", "a las 05:00 pm"},
+		{`schedule lunch at "Joe's Diner" tomorrow`, "schedule", "evento", "lunch at Joe's Diner", "Joe's Diner", "tomorrow", ""},
+		{"schedule meeting in the office at noon", "schedule", "evento", "meeting in the office", "the office", "", "at noon"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			p := nuevoParser(c.entrada, "en", Options{})
+			comando, err := p.Parse()
+			if err != nil {
+				t.Fatalf("errores inesperados: %v", p.Errors())
+			}
+			if comando.Locale != "en" {
+				t.Errorf("locale = %q", comando.Locale)
+			}
+			verbo, detalle, tiempo := partes(t, comando)
+			if verbo.Value != c.verbo || verbo.Tipo != c.tipo {
+				t.Errorf("verbo = %q (%s), se esperaba %q (%s)", verbo.Value, verbo.Tipo, c.verbo, c.tipo)
+			}
+			if got := strings.Join(detalle.Palabras, " "); got != c.palabras {
+				t.Errorf("palabras = %q, se esperaba %q", got, c.palabras)
+			}
+			if detalle.Lugar != c.lugar {
+				t.Errorf("lugar = %q, se esperaba %q", detalle.Lugar, c.lugar)
+			}
+			if got := textoFecha(tiempo.Fecha); got != c.fecha {
+				t.Errorf("fecha = %q, se esperaba %q", got, c.fecha)
+			}
+			if got := textoHora(tiempo.Hora); got != c.hora {
+				t.Errorf("hora = %q, se esperaba %q", got, c.hora)
+			}
+		})
+	}
+}
+
+func TestErroresEnIngles(t *testing.T) {
+	p := nuevoParser("schedule meeting at 25:00", "en", Options{})
+	if _, err := p.Parse(); err == nil || err.Error() != "hour out of range: 25" {
+		t.Errorf("error = %v, se esperaba el mensaje en inglés", err)
+	}
+}
+
+func TestParseHora(t *testing.T) {
+	casos := []struct {
+		entrada string
+		hora    string
+	}{
+		{"recordame algo a las 3 pm", "a las 03:00 pm"},
+		{"recordame algo a las 6 menos cuarto de la tarde", "a las 06 menos 15 de la tarde"},
+		{"recordame algo a las 8 y 10", "a las 08:10"},
+		{"recordame algo a las 8 y media", "a las 08:30"},
+		{"recordame algo al mediodía", "al mediodía"},
+		{"recordame algo a la medianoche", "a la medianoche"},
+		{"recordame algo a las 12 de la noche", "a las 12:00 de la noche"},
+		{"recordame algo a las 12 am", "a las 12:00 am"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, _, tiempo := sinErrores(t, c.entrada, Options{})
+			if got := textoHora(tiempo.Hora); got != c.hora {
+				t.Errorf("hora = %q, se esperaba %q", got, c.hora)
+			}
+		})
+	}
+}
+
+func TestFechaRelativa(t *testing.T) {
+	casos := []struct {
+		entrada string
+		tipo    string
+		offset  string // desplazamiento canónico, vacío si no hay
+	}{
+		{"agendá algo pasado mañana", "relativa", "+2 dia"},
+		{"agendá algo en 3 días", "desplazamiento", "+3 dia"},
+		{"agendá algo dentro de dos semanas", "desplazamiento", "+2 semana"},
+		{"agendá algo la semana que viene", "relativa", "+1 semana"},
+		{"agendá algo el mes próximo", "relativa", "+1 mes"},
+		{"agendá algo el próximo viernes", "diasemana", ""},
+		{"agendá algo este viernes", "diasemana", ""},
+		{"agendá algo fin de mes", "fin", ""},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, _, tiempo := sinErrores(t, c.entrada, Options{})
+			if tiempo.Fecha == nil {
+				t.Fatal("no se reconoció la fecha")
+			}
+			offset := ""
+			if tiempo.Fecha.Desplazamiento != nil {
+				offset = tiempo.Fecha.Desplazamiento.String()
+			}
+			if tiempo.Fecha.Tipo != c.tipo || offset != c.offset {
+				t.Errorf("fecha = %s %q, se esperaba %s %q", tiempo.Fecha.Tipo, offset, c.tipo, c.offset)
+			}
+		})
+	}
+}
+
+func TestAnio(t *testing.T) {
+	casos := []struct {
+		entrada string
+		anio    int
+		error   string // token del error; vacío si es válido
+	}{
+		{"agendá algo 15 de marzo de 2026", 2026, ""},
+		{"agendá algo 15 de marzo dos mil veintiséis", 2026, ""},
+		{"agendá algo 15/03/2100", 2100, ""},
+		{"agendá algo 1900-03-15", 1900, ""},
+		// Un año fuera de rango es un único error sobre el año
+		{"agendá algo 15 de marzo 99", 99, "99"},
+		{"agendá algo 15/03/25", 25, "25"},
+		{"agendá algo 2101-03-15", 2101, "2101"},
+		{"agendá algo 29 de febrero 1899", 1899, "1899"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			comando, errs := parsear(c.entrada, Options{})
+			if c.error == "" {
+				_, _, tiempo := sinErrores(t, c.entrada, Options{})
+				if tiempo.Fecha.Anio != c.anio {
+					t.Errorf("año = %d, se esperaba %d", tiempo.Fecha.Anio, c.anio)
+				}
+				return
+			}
+			if comando != nil || len(errs) != 1 {
+				t.Fatalf("errores = %v, se esperaba uno", errs)
+			}
+			if errs[0].Code != CodigoFechaInvalida || errs[0].Token != c.error {
+				t.Errorf("error = %s %q, se esperaba %s %q", errs[0].Code, errs[0].Token, CodigoFechaInvalida, c.error)
+			}
+		})
+	}
+}
+
+func TestTiempoEnCualquierLugar(t *testing.T) {
+	casos := []struct {
+		entrada     string
+		palabras    string
+		fecha, hora string
+	}{
+		{"mañana a las 10 agendá dentista", "dentista", "mañana", "a las 10:00"},
+		{"agendá mañana reunión con Ana", "reunión con Ana", "mañana", ""},
+		{"agendá el lunes reunión a las 9", "reunión", "lunes", "a las 09:00"},
+		{"a las 9 recordame el viernes pagar la luz", "pagar la luz", "viernes", "a las 09:00"},
+		{"agendá reunión a las 9 el lunes", "reunión", "lunes", "a las 09:00"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, detalle, tiempo := sinErrores(t, c.entrada, Options{})
+			if got := strings.Join(detalle.Palabras, " "); got != c.palabras {
+				t.Errorf("palabras = %q, se esperaba %q", got, c.palabras)
+			}
+			if got := textoFecha(tiempo.Fecha); got != c.fecha {
+				t.Errorf("fecha = %q, se esperaba %q", got, c.fecha)
+			}
+			if got := textoHora(tiempo.Hora); got != c.hora {
+				t.Errorf("hora = %q, se esperaba %q", got, c.hora)
+			}
+		})
+	}
+}
+
+func TestConflictoTiempo(t *testing.T) {
+	casos := []struct {
+		entrada string
+		token   string
+	}{
+		{"agendá reunión mañana y el lunes", "lunes"},
+		{"agendá el lunes reunión el martes", "martes"},
+		{"a las 10 agendá dentista a las 11", "a las 11"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, errs := parsear(c.entrada, Options{})
+			if len(errs) != 1 || errs[0].Code != CodigoConflictoTiempo || errs[0].Token != c.token {
+				t.Errorf("errores = %v, se esperaba %s en %q", errs, CodigoConflictoTiempo, c.token)
+			}
+		})
+	}
+}
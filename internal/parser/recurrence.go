@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/lexer"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/rrule"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+)
+
+// esInicioRecurrencia indica si el token actual comienza una recurrencia:
+// "cada" o "todos los" seguido de una unidad ("todos los días"), un día de
+// la semana ("cada lunes"), un número y una unidad o día ("cada 2 semanas")
+// o un día del mes ("cada 15 del mes")
+func (p *Parser) esInicioRecurrencia() bool {
+	if p.curToken.Type != lexer.CADA {
+		return false
+	}
+	switch p.peekToken.Type {
+	case lexer.UNIDAD, lexer.DIASEMANA:
+		return true
+	case lexer.NUMERO:
+		switch p.tokenEn(2).Type {
+		case lexer.UNIDAD, lexer.DIASEMANA:
+			return true
+		case lexer.DE:
+			return p.tokenEn(3).Type == lexer.UNIDAD
+		}
+	}
+	return false
+}
+
+// parseRecurrencia analiza la regla
+//
+//	RECURRENCIA → CADA ( [ NUMERO ] UNIDAD
+//	              | [ NUMERO ] DIA_SEMANA { "y" DIA_SEMANA }
+//	              | NUMERO "de" "mes" )
+//
+// El número antes de la unidad o del día es el intervalo ("cada 2
+// semanas"); antes de "del mes" es el día del mes ("cada 15 del mes"). Sólo
+// se llega aquí si esInicioRecurrencia vio una de esas formas.
+func (p *Parser) parseRecurrencia() (*ast.Recurrencia, *AnalyzerError) {
+	vocabulario := p.l.Vocabulary()
+	desde := p.curToken
+	recurrencia := &ast.Recurrencia{Intervalo: 1}
+	p.nextToken()
+
+	var numero *lexer.Token
+	if p.curToken.Type == lexer.NUMERO {
+		tok := p.curToken
+		numero = &tok
+		p.nextToken()
+	}
+
+	switch p.curToken.Type {
+	case lexer.DIASEMANA:
+		// "todos los lunes y jueves"
+		recurrencia.Unidad = vocab.UnitWeek
+		recurrencia.Dias = append(recurrencia.Dias, p.curToken.Keyword)
+		p.nextToken()
+		for p.curToken.Type == lexer.Y && p.peekToken.Type == lexer.DIASEMANA {
+			p.nextToken()
+			recurrencia.Dias = append(recurrencia.Dias, p.curToken.Keyword)
+			p.nextToken()
+		}
+	case lexer.DE:
+		// "cada 15 del mes": el número es el día, no el intervalo
+		p.nextToken()
+		if unidad, _ := vocabulario.UnitOf(p.curToken.Keyword); unidad != vocab.UnitMonth {
+			return nil, p.errorEn(p.curToken, CodigoRecurrenciaInvalida, esperaMesUnidad,
+				"sólo se puede repetir un día del mes, se encontró %s", p.curToken.Literal)
+		}
+		dia := valorNumero(*numero)
+		if dia < 1 || dia > 31 {
+			return nil, p.errorEn(*numero, CodigoRecurrenciaInvalida, nil, "día fuera de rango: %d", dia)
+		}
+		recurrencia.Unidad = vocab.UnitMonth
+		recurrencia.DiaDelMes = dia
+		numero = nil
+		p.nextToken()
+	default:
+		recurrencia.Unidad, _ = vocabulario.UnitOf(p.curToken.Keyword)
+		p.nextToken()
+	}
+
+	if numero != nil {
+		recurrencia.Intervalo = valorNumero(*numero)
+		switch {
+		case recurrencia.Intervalo < 1:
+			return nil, p.errorEn(*numero, CodigoRecurrenciaInvalida, nil,
+				"el intervalo de la repetición debe ser mayor que cero")
+		case recurrencia.Intervalo > rrule.MaxInterval:
+			return nil, p.errorEn(*numero, CodigoRecurrenciaInvalida, nil,
+				"el intervalo de la repetición no puede superar %d", rrule.MaxInterval)
+		}
+	}
+
+	recurrencia.Valor = p.l.Input()[desde.Pos:p.anterior().End]
+	return recurrencia, nil
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecurrencia(t *testing.T) {
+	casos := []struct {
+		entrada   string
+		unidad    string
+		intervalo int
+		dias      string
+		diaDelMes int
+		palabras  string
+	}{
+		{"recordame tomar la pastilla todos los días a las 8", "dia", 1, "", 0, "tomar la pastilla"},
+		{"agendá gimnasio todos los lunes y miércoles", "semana", 1, "lunes miércoles", 0, "gimnasio"},
+		{"agendá reunión cada 2 semanas", "semana", 2, "", 0, "reunión"},
+		{"agendá reunión cada dos lunes", "semana", 2, "lunes", 0, "reunión"},
+		{"recordame pagar el alquiler cada 15 del mes", "mes", 1, "", 15, "pagar el alquiler"},
+		{"agendá aniversario cada año", "anio", 1, "", 0, "aniversario"},
+		// "cada" sin unidad ni día es parte de la descripción
+		{"anotá que cada uno trae algo", "", 0, "", 0, "que cada uno trae algo"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.entrada, func(t *testing.T) {
+			_, detalle, tiempo := sinErrores(t, c.entrada, Options{})
+			if got := strings.Join(detalle.Palabras, " "); got != c.palabras {
+				t.Errorf("palabras = %q, se esperaba %q", got, c.palabras)
+			}
+			r := tiempo.Recurrencia
+			if c.unidad == "" {
+				if r != nil {
+					t.Errorf("recurrencia inesperada: %+v", r)
+				}
+				return
+			}
+			if r == nil {
+				t.Fatal("no se reconoció la recurrencia")
+			}
+			if r.Unidad != c.unidad || r.Intervalo != c.intervalo || strings.Join(r.Dias, " ") != c.dias || r.DiaDelMes != c.diaDelMes {
+				t.Errorf("recurrencia = %+v", r)
+			}
+		})
+	}
+}
+
+func TestRecurrenciaInvalida(t *testing.T) {
+	for _, entrada := range []string{"agendá reunión cada 0 semanas", "recordame pagar cada 40 del mes",
+		"agendá revisión cada 500000 semanas", "agendá revisión cada 5000 años", "agendá revisión cada 1001 días"} {
+		t.Run(entrada, func(t *testing.T) {
+			_, errs := parsear(entrada, Options{})
+			if len(errs) != 1 || errs[0].Code != CodigoRecurrenciaInvalida {
+				t.Errorf("errores = %v, se esperaba %s", errs, CodigoRecurrenciaInvalida)
+			}
+		})
+	}
+}
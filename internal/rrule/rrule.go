@@ -0,0 +1,371 @@
+// Package rrule implementa el subconjunto de las reglas de repetición de
+// RFC 5545 (RRULE) que producen los comandos: FREQ, INTERVAL, BYDAY (sin
+// ordinales) y BYMONTHDAY (un solo día). Las semanas empiezan el lunes
+// (WKST=MO). La primera ocurrencia se escribe como DTSTART, con la zona
+// horaria en TZID.
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency es la unidad con que se repite una regla
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// MaxInterval es el mayor intervalo aceptado ("cada 1000 días")
+const MaxInterval = 1000
+
+// Rule es una regla de repetición. Las ocurrencias se calculan a partir de
+// la primera (DTSTART), que da la hora y, si la regla no los indica, el día
+// de la semana o del mes.
+type Rule struct {
+	Freq       Frequency
+	Interval   int            // cada cuántas unidades; 0 equivale a 1
+	ByDay      []time.Weekday // días de la semana (WEEKLY)
+	ByMonthDay int            // día del mes (MONTHLY, YEARLY); 0 si no se indicó
+}
+
+// codigosDia son los nombres de los días en RFC 5545, empezando el domingo
+// como time.Weekday
+var codigosDia = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// String devuelve la regla en formato RRULE ("FREQ=WEEKLY;BYDAY=MO,TH")
+func (r Rule) String() string {
+	partes := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		partes = append(partes, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		dias := append([]time.Weekday(nil), r.ByDay...)
+		sort.Slice(dias, func(i, j int) bool { return diaSemana(dias[i]) < diaSemana(dias[j]) })
+		codigos := make([]string, len(dias))
+		for i, d := range dias {
+			codigos[i] = codigosDia[d]
+		}
+		partes = append(partes, "BYDAY="+strings.Join(codigos, ","))
+	}
+	if r.ByMonthDay != 0 {
+		partes = append(partes, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	return strings.Join(partes, ";")
+}
+
+// Parse lee una regla en formato RRULE. Sólo acepta las partes que genera
+// String.
+func Parse(s string) (Rule, error) {
+	var r Rule
+	for _, parte := range strings.Split(strings.TrimPrefix(s, "RRULE:"), ";") {
+		clave, valor, ok := strings.Cut(parte, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("rrule: parte inválida %q", parte)
+		}
+		switch clave {
+		case "FREQ":
+			switch Frequency(valor) {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = Frequency(valor)
+			default:
+				return Rule{}, fmt.Errorf("rrule: frecuencia no soportada %q", valor)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(valor)
+			if err != nil || n < 1 || n > MaxInterval {
+				return Rule{}, fmt.Errorf("rrule: intervalo inválido %q", valor)
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, codigo := range strings.Split(valor, ",") {
+				dia := indice(codigosDia, codigo)
+				if dia < 0 {
+					return Rule{}, fmt.Errorf("rrule: día inválido %q", codigo)
+				}
+				r.ByDay = append(r.ByDay, time.Weekday(dia))
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(valor)
+			if err != nil || n < 1 || n > 31 {
+				return Rule{}, fmt.Errorf("rrule: día del mes inválido %q", valor)
+			}
+			r.ByMonthDay = n
+		default:
+			return Rule{}, fmt.Errorf("rrule: parte no soportada %q", clave)
+		}
+	}
+	if r.Freq == "" {
+		return Rule{}, fmt.Errorf("rrule: falta FREQ en %q", s)
+	}
+	return r, nil
+}
+
+// Recurrence es una regla junto con su primera ocurrencia (DTSTART). Las
+// ocurrencias se calculan en la zona de Start, así "todos los lunes a las 9"
+// creado en Madrid sigue a las 9 de Madrid después del cambio de horario.
+type Recurrence struct {
+	Start time.Time
+	Rule  Rule
+}
+
+// formatoDTSTART es el formato de fecha y hora local de RFC 5545
+const formatoDTSTART = "20060102T150405"
+
+// String devuelve la recurrencia en formato RFC 5545, con DTSTART y RRULE en
+// líneas separadas:
+//
+//	DTSTART;TZID=Europe/Madrid:20251020T090000
+//	RRULE:FREQ=WEEKLY;BYDAY=MO
+//
+// En UTC la hora termina en Z; una zona de desplazamiento fijo sin nombre
+// también se escribe en UTC. La zona local del servidor se escribe sin TZID
+// (hora flotante).
+func (r Recurrence) String() string {
+	var inicio string
+	switch loc := r.Start.Location(); {
+	case loc == time.Local:
+		inicio = "DTSTART:" + r.Start.Format(formatoDTSTART)
+	case loc == time.UTC || loc.String() == "":
+		inicio = "DTSTART:" + r.Start.UTC().Format(formatoDTSTART) + "Z"
+	default:
+		inicio = "DTSTART;TZID=" + loc.String() + ":" + r.Start.Format(formatoDTSTART)
+	}
+	return inicio + "\nRRULE:" + r.Rule.String()
+}
+
+// ParseRecurrence lee una recurrencia escrita por Recurrence.String. También
+// acepta sólo la regla, sin DTSTART; en ese caso Start queda en cero.
+func ParseRecurrence(s string) (Recurrence, error) {
+	var r Recurrence
+	var regla string
+	for _, linea := range strings.Split(strings.TrimSpace(s), "\n") {
+		linea = strings.TrimSpace(linea)
+		if !strings.HasPrefix(linea, "DTSTART") {
+			regla = linea
+			continue
+		}
+		inicio, err := parseDTSTART(linea)
+		if err != nil {
+			return Recurrence{}, err
+		}
+		r.Start = inicio
+	}
+
+	var err error
+	r.Rule, err = Parse(regla)
+	return r, err
+}
+
+// parseDTSTART lee una línea DTSTART con TZID, en UTC o flotante
+func parseDTSTART(linea string) (time.Time, error) {
+	propiedad, valor, ok := strings.Cut(linea, ":")
+	if !ok {
+		return time.Time{}, fmt.Errorf("rrule: DTSTART inválido %q", linea)
+	}
+
+	loc := time.Local
+	switch {
+	case strings.HasPrefix(propiedad, "DTSTART;TZID="):
+		zona, err := time.LoadLocation(strings.TrimPrefix(propiedad, "DTSTART;TZID="))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("rrule: zona horaria inválida en %q", linea)
+		}
+		loc = zona
+	case propiedad != "DTSTART":
+		return time.Time{}, fmt.Errorf("rrule: DTSTART inválido %q", linea)
+	case strings.HasSuffix(valor, "Z"):
+		loc, valor = time.UTC, strings.TrimSuffix(valor, "Z")
+	}
+
+	inicio, err := time.ParseInLocation(formatoDTSTART, valor, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("rrule: fecha inválida en %q", linea)
+	}
+	return inicio, nil
+}
+
+// Between devuelve las ocurrencias de la regla que empieza en inicio
+// comprendidas en [desde, hasta). Las ocurrencias están en la zona de inicio
+// y conservan su hora local, aunque cambie el horario de verano. Un día del
+// mes que no existe (31 en abril) no genera ocurrencia ese mes.
+func (r Rule) Between(inicio, desde, hasta time.Time) []time.Time {
+	loc := inicio.Location()
+	desde, hasta = desde.In(loc), hasta.In(loc)
+	if desde.Before(inicio) {
+		desde = inicio
+	}
+
+	var ocurrencias []time.Time
+	dia := time.Date(desde.Year(), desde.Month(), desde.Day(), 0, 0, 0, 0, loc)
+	for ; dia.Before(hasta); dia = dia.AddDate(0, 0, 1) {
+		if !r.coincide(inicio, dia) {
+			continue
+		}
+		t := time.Date(dia.Year(), dia.Month(), dia.Day(), inicio.Hour(), inicio.Minute(), inicio.Second(), 0, loc)
+		if !t.Before(desde) && t.Before(hasta) {
+			ocurrencias = append(ocurrencias, t)
+		}
+	}
+	return ocurrencias
+}
+
+// periodosMaximos es cuántos periodos de la regla revisa First: los meses se
+// repiten cada 12 y los años bisiestos cada 400
+const periodosMaximos = 400
+
+// First devuelve la primera ocurrencia de la regla, a la hora de inicio,
+// que no sea anterior a desde. Si inicio no coincide con la regla ("todos
+// los lunes" pedido un domingo) la primera ocurrencia es posterior. Se
+// calcula por periodos de la regla (días, semanas, meses o años) a partir
+// del que contiene a desde, así el costo no depende del intervalo. Si la
+// regla nunca coincide (el 31 cada 12 meses desde abril) devuelve inicio.
+func (r Rule) First(inicio, desde time.Time) time.Time {
+	loc := inicio.Location()
+	desde = desde.In(loc)
+	if desde.Before(inicio) {
+		desde = inicio
+	}
+
+	intervalo := max(r.Interval, 1)
+	var periodo int
+	switch r.Freq {
+	case Daily:
+		periodo = diasEntre(inicio, desde) / intervalo
+	case Weekly:
+		periodo = diasEntre(inicioSemana(inicio), inicioSemana(desde)) / 7 / intervalo
+	case Monthly:
+		periodo = mesesEntre(inicio, desde) / intervalo
+	case Yearly:
+		periodo = (desde.Year() - inicio.Year()) / intervalo
+	}
+
+	for n := periodo; n <= periodo+periodosMaximos; n++ {
+		for _, dia := range r.diasDelPeriodo(inicio, n*intervalo) {
+			t := time.Date(dia.Year(), dia.Month(), dia.Day(), inicio.Hour(), inicio.Minute(), inicio.Second(), 0, loc)
+			if !t.Before(desde) {
+				return t
+			}
+		}
+	}
+	return inicio
+}
+
+// diasDelPeriodo devuelve, en orden, los días que coinciden con la regla en
+// el periodo que está unidades días, semanas, meses o años después del de
+// inicio
+func (r Rule) diasDelPeriodo(inicio time.Time, unidades int) []time.Time {
+	loc := inicio.Location()
+	diaDelMes := r.ByMonthDay
+	if diaDelMes == 0 {
+		diaDelMes = inicio.Day()
+	}
+
+	switch r.Freq {
+	case Daily:
+		return []time.Time{time.Date(inicio.Year(), inicio.Month(), inicio.Day()+unidades, 0, 0, 0, 0, loc)}
+	case Weekly:
+		lunes := inicioSemana(inicio)
+		lunes = time.Date(lunes.Year(), lunes.Month(), lunes.Day()+7*unidades, 0, 0, 0, 0, loc)
+		dias := r.ByDay
+		if len(dias) == 0 {
+			dias = []time.Weekday{inicio.Weekday()}
+		}
+		var resultado []time.Time
+		for _, d := range dias {
+			resultado = append(resultado, lunes.AddDate(0, 0, diaSemana(d)-1))
+		}
+		sort.Slice(resultado, func(i, j int) bool { return resultado[i].Before(resultado[j]) })
+		return resultado
+	case Monthly:
+		return diaDelMesEn(time.Date(inicio.Year(), inicio.Month()+time.Month(unidades), 1, 0, 0, 0, 0, loc), diaDelMes)
+	case Yearly:
+		return diaDelMesEn(time.Date(inicio.Year()+unidades, inicio.Month(), 1, 0, 0, 0, 0, loc), diaDelMes)
+	}
+	return nil
+}
+
+// diaDelMesEn devuelve el día del mes que empieza en primero, o ninguno si
+// el mes no lo tiene (31 en abril, 29 de febrero en un año común)
+func diaDelMesEn(primero time.Time, dia int) []time.Time {
+	t := primero.AddDate(0, 0, dia-1)
+	if t.Month() != primero.Month() {
+		return nil
+	}
+	return []time.Time{t}
+}
+
+// coincide indica si el día es una ocurrencia de la regla que empieza en inicio
+func (r Rule) coincide(inicio, dia time.Time) bool {
+	intervalo := max(r.Interval, 1)
+	diaDelMes := r.ByMonthDay
+	if diaDelMes == 0 {
+		diaDelMes = inicio.Day()
+	}
+
+	switch r.Freq {
+	case Daily:
+		return diasEntre(inicio, dia)%intervalo == 0
+	case Weekly:
+		semanas := diasEntre(inicioSemana(inicio), inicioSemana(dia)) / 7
+		if semanas%intervalo != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return dia.Weekday() == inicio.Weekday()
+		}
+		for _, d := range r.ByDay {
+			if dia.Weekday() == d {
+				return true
+			}
+		}
+		return false
+	case Monthly:
+		return mesesEntre(inicio, dia)%intervalo == 0 && dia.Day() == diaDelMes
+	case Yearly:
+		return (dia.Year()-inicio.Year())%intervalo == 0 && dia.Month() == inicio.Month() && dia.Day() == diaDelMes
+	}
+	return false
+}
+
+// diasEntre cuenta los días de calendario entre dos fechas, sin que influya
+// el horario de verano
+func diasEntre(desde, hasta time.Time) int {
+	a := time.Date(desde.Year(), desde.Month(), desde.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(hasta.Year(), hasta.Month(), hasta.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// mesesEntre cuenta los meses de calendario entre dos fechas
+func mesesEntre(desde, hasta time.Time) int {
+	return (hasta.Year()-desde.Year())*12 + int(hasta.Month()-desde.Month())
+}
+
+// inicioSemana devuelve el lunes de la semana de t
+func inicioSemana(t time.Time) time.Time {
+	return t.AddDate(0, 0, 1-diaSemana(t.Weekday()))
+}
+
+// diaSemana numera los días de lunes (1) a domingo (7)
+func diaSemana(d time.Weekday) int {
+	if d == time.Sunday {
+		return 7
+	}
+	return int(d)
+}
+
+func indice(lista []string, valor string) int {
+	for i, v := range lista {
+		if v == valor {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,146 @@
+package rrule
+
+import (
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata" // las pruebas no dependen de la base IANA del sistema
+)
+
+func TestParse(t *testing.T) {
+	casos := []string{
+		"FREQ=DAILY",
+		"FREQ=WEEKLY;BYDAY=MO,WE",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+		"FREQ=MONTHLY;BYMONTHDAY=15",
+		"FREQ=YEARLY",
+	}
+	for _, s := range casos {
+		r, err := Parse(s)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+			continue
+		}
+		if r.String() != s {
+			t.Errorf("Parse(%q).String() = %q", s, r.String())
+		}
+	}
+
+	for _, s := range []string{"", "FREQ=HOURLY", "FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;INTERVAL=1001", "FREQ=WEEKLY;BYDAY=XX", "FREQ=MONTHLY;BYMONTHDAY=32", "BYDAY=MO"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): se esperaba un error", s)
+		}
+	}
+}
+
+func TestRecurrence(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	regla := Rule{Freq: Weekly, ByDay: []time.Weekday{time.Monday}}
+
+	casos := []struct {
+		inicio time.Time
+		texto  string
+	}{
+		{time.Date(2025, time.October, 20, 9, 0, 0, 0, madrid), "DTSTART;TZID=Europe/Madrid:20251020T090000\nRRULE:FREQ=WEEKLY;BYDAY=MO"},
+		{time.Date(2025, time.October, 20, 9, 0, 0, 0, time.UTC), "DTSTART:20251020T090000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO"},
+		{time.Date(2025, time.October, 20, 9, 0, 0, 0, time.FixedZone("", -3*3600)), "DTSTART:20251020T120000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO"},
+	}
+	for _, c := range casos {
+		texto := Recurrence{Start: c.inicio, Rule: regla}.String()
+		if texto != c.texto {
+			t.Errorf("String() = %q, se esperaba %q", texto, c.texto)
+		}
+		r, err := ParseRecurrence(texto)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q): %v", texto, err)
+		}
+		if !r.Start.Equal(c.inicio) || r.Rule.String() != regla.String() {
+			t.Errorf("ParseRecurrence(%q) = %v %s", texto, r.Start, r.Rule)
+		}
+	}
+
+	// Sin DTSTART sólo se lee la regla
+	if r, err := ParseRecurrence("FREQ=DAILY"); err != nil || !r.Start.IsZero() || r.Rule.Freq != Daily {
+		t.Errorf("ParseRecurrence(FREQ=DAILY) = %+v, %v", r, err)
+	}
+	if _, err := ParseRecurrence("DTSTART;TZID=Marte/Olimpo:20251020T090000\nRRULE:FREQ=DAILY"); err == nil {
+		t.Error("se esperaba un error por la zona horaria inválida")
+	}
+}
+
+func TestBetween(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Lunes 20 de octubre de 2025 a las 9 en Madrid; el 26 cambia el horario
+	inicio := time.Date(2025, time.October, 20, 9, 0, 0, 0, madrid)
+	desde := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	hasta := time.Date(2025, time.November, 4, 0, 0, 0, 0, time.UTC)
+
+	casos := []struct {
+		regla  Rule
+		fechas []string // en UTC
+	}{
+		{Rule{Freq: Weekly}, []string{"2025-10-20 07:00", "2025-10-27 08:00", "2025-11-03 08:00"}},
+		{Rule{Freq: Weekly, Interval: 2}, []string{"2025-10-20 07:00", "2025-11-03 08:00"}},
+		{Rule{Freq: Monthly, ByMonthDay: 31}, []string{"2025-10-31 08:00"}},
+		{Rule{Freq: Daily, Interval: 5}, []string{"2025-10-20 07:00", "2025-10-25 07:00", "2025-10-30 08:00"}},
+	}
+	for _, c := range casos {
+		var fechas []string
+		for _, f := range c.regla.Between(inicio, desde, hasta) {
+			fechas = append(fechas, f.UTC().Format("2006-01-02 15:04"))
+		}
+		if !slices.Equal(fechas, c.fechas) {
+			t.Errorf("%s: ocurrencias = %v, se esperaba %v", c.regla, fechas, c.fechas)
+		}
+	}
+}
+
+func TestFirst(t *testing.T) {
+	// Miércoles 15 de octubre de 2025 a las 9
+	inicio := time.Date(2025, time.October, 15, 9, 0, 0, 0, time.UTC)
+	bisiesto := time.Date(2000, time.February, 29, 9, 0, 0, 0, time.UTC)
+	abril := time.Date(2025, time.April, 10, 9, 0, 0, 0, time.UTC)
+
+	casos := []struct {
+		nombre        string
+		regla         Rule
+		inicio, desde time.Time
+		primera       time.Time
+	}{
+		{"próximo lunes", Rule{Freq: Weekly, ByDay: []time.Weekday{time.Monday}}, inicio, inicio,
+			time.Date(2025, time.October, 20, 9, 0, 0, 0, time.UTC)},
+		{"día 31", Rule{Freq: Monthly, ByMonthDay: 31}, inicio, time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, time.December, 31, 9, 0, 0, 0, time.UTC)},
+		{"29 de febrero", Rule{Freq: Yearly}, bisiesto, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2028, time.February, 29, 9, 0, 0, 0, time.UTC)},
+		{"intervalo máximo", Rule{Freq: Weekly, Interval: MaxInterval}, inicio, inicio.AddDate(0, 0, 1),
+			time.Date(2044, time.December, 14, 9, 0, 0, 0, time.UTC)},
+		// 3000 no es bisiesto, 4000 sí
+		{"29 de febrero cada 1000 años", Rule{Freq: Yearly, Interval: 1000}, bisiesto, bisiesto.AddDate(1, 0, 0),
+			time.Date(4000, time.February, 29, 9, 0, 0, 0, time.UTC)},
+		// Una regla armada a mano, sin el límite del parser
+		{"intervalo enorme", Rule{Freq: Weekly, Interval: 500000}, inicio, inicio.AddDate(0, 0, 1),
+			inicio.AddDate(0, 0, 7*500000)},
+		// Abril nunca tiene 31: se devuelve el inicio
+		{"sin ocurrencias", Rule{Freq: Monthly, Interval: 12, ByMonthDay: 31}, abril, abril, abril},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			comienzo := time.Now()
+			primera := c.regla.First(c.inicio, c.desde)
+			if d := time.Since(comienzo); d > 50*time.Millisecond {
+				t.Errorf("First tardó %v; no debe depender del intervalo", d)
+			}
+			if !primera.Equal(c.primera) {
+				t.Errorf("primera = %v, se esperaba %v", primera, c.primera)
+			}
+		})
+	}
+}
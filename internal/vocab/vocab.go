@@ -0,0 +1,798 @@
+package vocab
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale es el idioma que se usa cuando no se pide otro o el pedido
+// no está disponible
+const DefaultLocale = "es"
+
+// embebidos contiene los vocabularios incluidos en el binario, uno por idioma
+//
+//go:embed es.json en.json
+var embebidos embed.FS
+
+// Verb es un verbo que inicia un comando y el tipo de acción que crea
+type Verb struct {
+	Word string `json:"word"`
+	Type string `json:"type"`
+}
+
+// Priority es una palabra o frase que marca la prioridad de la acción
+// ("urgente", "prioridad alta") y el nivel que le asigna
+type Priority struct {
+	Word     string `json:"word"`
+	Priority string `json:"priority"`
+}
+
+// RelativeDate es una fecha relativa expresada como una cantidad de unidades
+// desde hoy ("pasado mañana" → 2 días, "la semana que viene" → 1 semana)
+type RelativeDate struct {
+	Word   string `json:"word"`
+	Amount int    `json:"amount"`
+	Unit   string `json:"unit"`
+}
+
+// Unit es una palabra que nombra una unidad de tiempo ("días" → "dia")
+type Unit struct {
+	Word string `json:"word"`
+	Unit string `json:"unit"`
+}
+
+// Modifier acompaña a un día de la semana: "proximo" ("el próximo viernes",
+// el de la semana que viene) o "este" ("este viernes", el de esta semana)
+type Modifier struct {
+	Word     string `json:"word"`
+	Modifier string `json:"modifier"`
+}
+
+// PeriodEnd nombra el último día de una unidad de tiempo ("fin de mes")
+type PeriodEnd struct {
+	Word string `json:"word"`
+	Unit string `json:"unit"`
+}
+
+// Weekday es un día de la semana; Day sigue a time.Weekday (0 = domingo)
+type Weekday struct {
+	Word string `json:"word"`
+	Day  int    `json:"day"`
+}
+
+// Month es un mes del año (1 = enero)
+type Month struct {
+	Word  string `json:"word"`
+	Month int    `json:"month"`
+}
+
+// Period es una frase que acompaña a la hora ("de la tarde", "pm"). From y
+// To delimitan, en formato 24h, las horas que abarca el periodo (To es
+// exclusivo y puede ser menor que From si el periodo cruza la medianoche).
+// Si son iguales el periodo no cambia la hora ("hs").
+type Period struct {
+	Word string `json:"word"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+// FixedTime es una expresión que nombra una hora exacta ("al mediodía")
+type FixedTime struct {
+	Word   string `json:"word"`
+	Hour   int    `json:"hour"`
+	Minute int    `json:"minute"`
+}
+
+// Fraction es una fracción de hora ("media", "cuarto") expresada en minutos
+type Fraction struct {
+	Word    string `json:"word"`
+	Minutes int    `json:"minutes"`
+}
+
+// DurationUnit es una unidad de duración ("hora", "minutos") expresada en minutos
+type DurationUnit struct {
+	Word    string `json:"word"`
+	Minutes int    `json:"minutes"`
+}
+
+// Number es una palabra que nombra un número ("cinco", "veinte", "primero").
+// Las palabras con Multiplier multiplican lo leído antes ("dos mil"); las
+// demás se suman ("mil novecientos", "treinta y uno").
+type Number struct {
+	Word       string `json:"word"`
+	Value      int    `json:"value"`
+	Multiplier bool   `json:"multiplier,omitempty"`
+}
+
+// Connector es una palabra o frase de enlace y el token que produce
+type Connector struct {
+	Word  string `json:"word"`
+	Token string `json:"token"`
+}
+
+// Vocabulary contiene todas las palabras clave del lenguaje de comandos en un
+// idioma. En cada lista, la primera palabra es la forma canónica cuando
+// varias coinciden al normalizar ("miércoles" y "miercoles").
+type Vocabulary struct {
+	Locale         string         `json:"locale"`
+	ActionTypes    []string       `json:"actionTypes"`
+	Verbs          []Verb         `json:"verbs"`
+	EventTypes     []string       `json:"eventTypes"`
+	PriorityLevels []string       `json:"priorityLevels"` // de mayor a menor
+	Priorities     []Priority     `json:"priorities"`
+	RelativeDates  []RelativeDate `json:"relativeDates"`
+	Units          []Unit         `json:"units"`
+	Modifiers      []Modifier     `json:"modifiers"`
+	PeriodEnds     []PeriodEnd    `json:"periodEnds"`
+	Weekdays       []Weekday      `json:"weekdays"`
+	Months         []Month        `json:"months"`
+	Periods        []Period       `json:"periods"`
+	FixedTimes     []FixedTime    `json:"fixedTimes"`
+	Fractions      []Fraction     `json:"fractions"`
+	Connectors     []Connector    `json:"connectors"`
+	Numbers        []Number       `json:"numbers"`
+	DurationUnits  []DurationUnit `json:"durationUnits"`
+	NumberJoiners  []string       `json:"numberJoiners"` // "y" en "treinta y uno"
+	Articles       []string       `json:"articles"`      // "la" en "en la oficina", parte del lugar o la persona
+	PlaceMarkers   []string       `json:"placeMarkers"`  // "at" en "at the office": comienza el lugar si le sigue un artículo o un nombre propio
+	Conjunctions   []string       `json:"conjunctions"`  // "luego" en "agendá dentista y luego recordame comprar pan", separa comandos
+	Measures       []string       `json:"measures"`      // "kilo" en "comprar 1/2 kilo": después de "1/2" indica una cantidad, no una fecha
+	DateOrder      string         `json:"dateOrder"`     // orden de las fechas numéricas: "dmy" (15/03) o "mdy" (03/15)
+}
+
+// Unidades de tiempo canónicas
+const (
+	UnitDay   = "dia"
+	UnitWeek  = "semana"
+	UnitMonth = "mes"
+	UnitYear  = "anio"
+)
+
+// units son las unidades de tiempo válidas
+var units = []string{UnitDay, UnitWeek, UnitMonth, UnitYear}
+
+// Modificadores de día de la semana
+const (
+	ModifierNext = "proximo"
+	ModifierThis = "este"
+)
+
+// Órdenes de las fechas numéricas con barras
+const (
+	DateOrderDMY = "dmy"
+	DateOrderMDY = "mdy"
+)
+
+// connectorTokens son los tokens que puede producir un conector
+var connectorTokens = []string{"CON", "DE", "ALAS", "EL", "Y", "MENOS", "EN", "CADA", "DESDE", "HASTA", "DURANTE", "SOBRE"}
+
+var (
+	mu           sync.RWMutex
+	carga        sync.Once
+	vocabularios = map[string]*Vocabulary{}
+)
+
+// registro devuelve los vocabularios registrados. La primera vez registra
+// los embebidos en el binario.
+func registro() map[string]*Vocabulary {
+	carga.Do(func() {
+		for _, nombre := range []string{"es.json", "en.json"} {
+			data, _ := embebidos.ReadFile(nombre)
+			v, err := Parse(data)
+			if err != nil {
+				panic(fmt.Sprintf("vocabulario embebido %s inválido: %v", nombre, err))
+			}
+			mu.Lock()
+			vocabularios[v.Locale] = v
+			mu.Unlock()
+		}
+	})
+	return vocabularios
+}
+
+// Current devuelve el vocabulario del idioma por defecto
+func Current() *Vocabulary {
+	return Get(DefaultLocale)
+}
+
+// Get devuelve el vocabulario de un idioma ("en", "en-US", "es_AR"). Si el
+// idioma no está disponible devuelve el del idioma por defecto.
+func Get(locale string) *Vocabulary {
+	vs := registro()
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if v, ok := vs[base(locale)]; ok {
+		return v
+	}
+	return vs[DefaultLocale]
+}
+
+// Supported indica si hay un vocabulario para el idioma y devuelve su código
+func Supported(locale string) (string, bool) {
+	vs := registro()
+	mu.RLock()
+	defer mu.RUnlock()
+
+	_, ok := vs[base(locale)]
+	return base(locale), ok
+}
+
+// Locales devuelve los idiomas disponibles, empezando por el idioma por defecto
+func Locales() []string {
+	vs := registro()
+	mu.RLock()
+	defer mu.RUnlock()
+
+	locales := []string{DefaultLocale}
+	for locale := range vs {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales[1:])
+	return locales
+}
+
+// Match elige el idioma disponible que mejor coincide con las preferencias,
+// por ejemplo el valor de un encabezado Accept-Language ("en-US,en;q=0.9").
+// Si ninguna coincide devuelve el idioma por defecto.
+func Match(preferencias ...string) string {
+	locales := Locales()
+	tags := make([]language.Tag, len(locales))
+	for i, locale := range locales {
+		tags[i] = language.Make(locale)
+	}
+
+	_, indice, confianza := language.NewMatcher(tags).Match(parsearPreferencias(preferencias)...)
+	if confianza == language.No {
+		return DefaultLocale
+	}
+	return locales[indice]
+}
+
+// parsearPreferencias convierte las preferencias en etiquetas de idioma,
+// ignorando las que no se pueden interpretar
+func parsearPreferencias(preferencias []string) []language.Tag {
+	var tags []language.Tag
+	for _, pref := range preferencias {
+		parsed, _, err := language.ParseAcceptLanguage(pref)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, parsed...)
+	}
+	return tags
+}
+
+// Set registra un vocabulario para su idioma, reemplazando el anterior
+func Set(v *Vocabulary) {
+	vs := registro()
+	mu.Lock()
+	defer mu.Unlock()
+
+	if v.Locale == "" {
+		v.Locale = DefaultLocale
+	}
+	vs[v.Locale] = v
+}
+
+// Default devuelve el vocabulario embebido del idioma por defecto, sin los
+// cambios hechos con Set o LoadFile
+func Default() *Vocabulary {
+	data, _ := embebidos.ReadFile(DefaultLocale + ".json")
+	v, err := Parse(data)
+	if err != nil {
+		panic(fmt.Sprintf("vocabulario embebido inválido: %v", err))
+	}
+	return v
+}
+
+// LoadFile lee un vocabulario desde un archivo JSON y lo registra para el
+// idioma indicado en su campo locale (por defecto, español)
+func LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error al leer el vocabulario: %v", err)
+	}
+
+	v, err := Parse(data)
+	if err != nil {
+		return fmt.Errorf("error en el vocabulario %s: %v", path, err)
+	}
+
+	Set(v)
+	return nil
+}
+
+// Parse decodifica y valida un vocabulario en JSON
+func Parse(data []byte) (*Vocabulary, error) {
+	var v Vocabulary
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	v.Locale = base(v.Locale)
+	if v.Locale == "" {
+		v.Locale = DefaultLocale
+	}
+	return &v, nil
+}
+
+// Validate verifica que el vocabulario esté completo y sea coherente
+func (v *Vocabulary) Validate() error {
+	if len(v.Verbs) == 0 {
+		return fmt.Errorf("se necesita al menos un verbo")
+	}
+	if len(v.ActionTypes) == 0 {
+		return fmt.Errorf("se necesita al menos un tipo de acción")
+	}
+	for _, verb := range v.Verbs {
+		if strings.TrimSpace(verb.Word) == "" {
+			return fmt.Errorf("verbo sin palabra")
+		}
+		if verb.Type == "" {
+			return fmt.Errorf("el verbo '%s' no tiene tipo", verb.Word)
+		}
+		if !contiene(v.ActionTypes, verb.Type) {
+			return fmt.Errorf("el verbo '%s' usa el tipo '%s', que no está en actionTypes", verb.Word, verb.Type)
+		}
+	}
+	for _, w := range v.EventTypes {
+		if strings.TrimSpace(w) == "" {
+			return fmt.Errorf("tipo de evento vacío")
+		}
+	}
+	for _, prioridad := range v.Priorities {
+		if strings.TrimSpace(prioridad.Word) == "" {
+			return fmt.Errorf("prioridad sin palabra")
+		}
+		if !contiene(v.PriorityLevels, prioridad.Priority) {
+			return fmt.Errorf("la prioridad '%s' usa el nivel '%s', que no está en priorityLevels", prioridad.Word, prioridad.Priority)
+		}
+	}
+	for _, r := range v.RelativeDates {
+		if strings.TrimSpace(r.Word) == "" {
+			return fmt.Errorf("fecha relativa sin palabra")
+		}
+		if !contiene(units, r.Unit) {
+			return fmt.Errorf("la fecha relativa '%s' usa una unidad inválida: '%s'", r.Word, r.Unit)
+		}
+	}
+	for _, u := range v.Units {
+		if strings.TrimSpace(u.Word) == "" || !contiene(units, u.Unit) {
+			return fmt.Errorf("unidad de tiempo inválida: '%s' (%s)", u.Word, u.Unit)
+		}
+	}
+	for _, m := range v.Modifiers {
+		if strings.TrimSpace(m.Word) == "" || (m.Modifier != ModifierNext && m.Modifier != ModifierThis) {
+			return fmt.Errorf("modificador inválido: '%s' (%s)", m.Word, m.Modifier)
+		}
+	}
+	for _, e := range v.PeriodEnds {
+		if strings.TrimSpace(e.Word) == "" || e.Unit == UnitDay || !contiene(units, e.Unit) {
+			return fmt.Errorf("fin de periodo inválido: '%s' (%s)", e.Word, e.Unit)
+		}
+	}
+	for _, d := range v.Weekdays {
+		if strings.TrimSpace(d.Word) == "" || d.Day < 0 || d.Day > 6 {
+			return fmt.Errorf("día de la semana inválido: '%s' (%d)", d.Word, d.Day)
+		}
+	}
+	for _, m := range v.Months {
+		if strings.TrimSpace(m.Word) == "" || m.Month < 1 || m.Month > 12 {
+			return fmt.Errorf("mes inválido: '%s' (%d)", m.Word, m.Month)
+		}
+	}
+	for _, p := range v.Periods {
+		if strings.TrimSpace(p.Word) == "" {
+			return fmt.Errorf("periodo sin palabra")
+		}
+		if p.From < 0 || p.From > 24 || p.To < 0 || p.To > 24 {
+			return fmt.Errorf("periodo '%s' fuera de rango: %d-%d", p.Word, p.From, p.To)
+		}
+	}
+	for _, f := range v.FixedTimes {
+		if strings.TrimSpace(f.Word) == "" || f.Hour < 0 || f.Hour > 23 || f.Minute < 0 || f.Minute > 59 {
+			return fmt.Errorf("hora fija inválida: '%s' (%02d:%02d)", f.Word, f.Hour, f.Minute)
+		}
+	}
+	for _, f := range v.Fractions {
+		if strings.TrimSpace(f.Word) == "" || f.Minutes < 1 || f.Minutes > 59 {
+			return fmt.Errorf("fracción de hora inválida: '%s' (%d)", f.Word, f.Minutes)
+		}
+	}
+	for _, c := range v.Connectors {
+		if strings.TrimSpace(c.Word) == "" || !contiene(connectorTokens, c.Token) {
+			return fmt.Errorf("conector inválido: '%s' (%s)", c.Word, c.Token)
+		}
+	}
+	for _, n := range v.Numbers {
+		if strings.TrimSpace(n.Word) == "" || n.Value < 0 || (n.Multiplier && n.Value != 100 && n.Value != 1000) {
+			return fmt.Errorf("número inválido: '%s' (%d)", n.Word, n.Value)
+		}
+	}
+	if v.DateOrder != "" && v.DateOrder != DateOrderDMY && v.DateOrder != DateOrderMDY {
+		return fmt.Errorf("orden de fecha inválido: '%s'", v.DateOrder)
+	}
+	for _, d := range v.DurationUnits {
+		if strings.TrimSpace(d.Word) == "" || d.Minutes < 1 {
+			return fmt.Errorf("unidad de duración inválida: '%s' (%d)", d.Word, d.Minutes)
+		}
+	}
+	for _, a := range v.Articles {
+		if strings.TrimSpace(a) == "" {
+			return fmt.Errorf("artículo vacío")
+		}
+	}
+	for _, m := range v.PlaceMarkers {
+		if strings.TrimSpace(m) == "" {
+			return fmt.Errorf("marca de lugar vacía")
+		}
+	}
+	for _, c := range v.Conjunctions {
+		if strings.TrimSpace(c) == "" {
+			return fmt.Errorf("conjunción vacía")
+		}
+	}
+	for _, m := range v.Measures {
+		if strings.TrimSpace(m) == "" {
+			return fmt.Errorf("unidad de medida vacía")
+		}
+	}
+	for _, j := range v.NumberJoiners {
+		if strings.TrimSpace(j) == "" {
+			return fmt.Errorf("conector de números vacío")
+		}
+	}
+	return nil
+}
+
+// VerbWords devuelve todos los verbos
+func (v *Vocabulary) VerbWords() []string {
+	words := make([]string, len(v.Verbs))
+	for i, verb := range v.Verbs {
+		words[i] = verb.Word
+	}
+	return words
+}
+
+// VerbType devuelve el tipo de acción asociado a un verbo
+func (v *Vocabulary) VerbType(word string) (string, bool) {
+	for _, verb := range v.Verbs {
+		if verb.Word == word {
+			return verb.Type, true
+		}
+	}
+	return "", false
+}
+
+// PriorityWords devuelve todas las palabras de prioridad
+func (v *Vocabulary) PriorityWords() []string {
+	words := make([]string, len(v.Priorities))
+	for i, prioridad := range v.Priorities {
+		words[i] = prioridad.Word
+	}
+	return words
+}
+
+// PriorityOf devuelve el nivel de prioridad que asigna una palabra
+func (v *Vocabulary) PriorityOf(word string) (string, bool) {
+	for _, prioridad := range v.Priorities {
+		if prioridad.Word == word {
+			return prioridad.Priority, true
+		}
+	}
+	return "", false
+}
+
+// IsPriorityLevel indica si el nivel de prioridad existe en el vocabulario
+func (v *Vocabulary) IsPriorityLevel(level string) bool {
+	return contiene(v.PriorityLevels, level)
+}
+
+// RelativeDateWords devuelve todas las fechas relativas
+func (v *Vocabulary) RelativeDateWords() []string {
+	words := make([]string, len(v.RelativeDates))
+	for i, r := range v.RelativeDates {
+		words[i] = r.Word
+	}
+	return words
+}
+
+// RelativeOffset devuelve la cantidad y la unidad de una fecha relativa
+func (v *Vocabulary) RelativeOffset(word string) (amount int, unit string, ok bool) {
+	for _, r := range v.RelativeDates {
+		if r.Word == word {
+			return r.Amount, r.Unit, true
+		}
+	}
+	return 0, "", false
+}
+
+// UnitWords devuelve todas las palabras de unidades de tiempo
+func (v *Vocabulary) UnitWords() []string {
+	words := make([]string, len(v.Units))
+	for i, u := range v.Units {
+		words[i] = u.Word
+	}
+	return words
+}
+
+// UnitOf devuelve la unidad canónica de una palabra ("semanas" → "semana")
+func (v *Vocabulary) UnitOf(word string) (string, bool) {
+	for _, u := range v.Units {
+		if u.Word == word {
+			return u.Unit, true
+		}
+	}
+	return "", false
+}
+
+// ModifierWords devuelve todos los modificadores de día de la semana
+func (v *Vocabulary) ModifierWords() []string {
+	words := make([]string, len(v.Modifiers))
+	for i, m := range v.Modifiers {
+		words[i] = m.Word
+	}
+	return words
+}
+
+// ModifierOf devuelve el modificador canónico de una palabra ("que viene" → "proximo")
+func (v *Vocabulary) ModifierOf(word string) (string, bool) {
+	for _, m := range v.Modifiers {
+		if m.Word == word {
+			return m.Modifier, true
+		}
+	}
+	return "", false
+}
+
+// PeriodEndWords devuelve todas las expresiones de fin de periodo
+func (v *Vocabulary) PeriodEndWords() []string {
+	words := make([]string, len(v.PeriodEnds))
+	for i, e := range v.PeriodEnds {
+		words[i] = e.Word
+	}
+	return words
+}
+
+// PeriodEndOf devuelve la unidad cuyo fin nombra la expresión ("fin de mes" → "mes")
+func (v *Vocabulary) PeriodEndOf(word string) (string, bool) {
+	for _, e := range v.PeriodEnds {
+		if e.Word == word {
+			return e.Unit, true
+		}
+	}
+	return "", false
+}
+
+// WeekdayWords devuelve todos los días de la semana
+func (v *Vocabulary) WeekdayWords() []string {
+	words := make([]string, len(v.Weekdays))
+	for i, d := range v.Weekdays {
+		words[i] = d.Word
+	}
+	return words
+}
+
+// WeekdayNumber devuelve el número de día (0 = domingo) de un día de la semana
+func (v *Vocabulary) WeekdayNumber(word string) (int, bool) {
+	for _, d := range v.Weekdays {
+		if d.Word == word {
+			return d.Day, true
+		}
+	}
+	return 0, false
+}
+
+// MonthWords devuelve todos los meses
+func (v *Vocabulary) MonthWords() []string {
+	words := make([]string, len(v.Months))
+	for i, m := range v.Months {
+		words[i] = m.Word
+	}
+	return words
+}
+
+// MonthNumber devuelve el número de mes (1 = enero) de un nombre de mes
+func (v *Vocabulary) MonthNumber(word string) (int, bool) {
+	for _, m := range v.Months {
+		if m.Word == word {
+			return m.Month, true
+		}
+	}
+	return 0, false
+}
+
+// MonthWord devuelve el nombre canónico de un mes (1 = enero)
+func (v *Vocabulary) MonthWord(month int) (string, bool) {
+	for _, m := range v.Months {
+		if m.Month == month {
+			return m.Word, true
+		}
+	}
+	return "", false
+}
+
+// PeriodWords devuelve todos los periodos
+func (v *Vocabulary) PeriodWords() []string {
+	words := make([]string, len(v.Periods))
+	for i, p := range v.Periods {
+		words[i] = p.Word
+	}
+	return words
+}
+
+// base devuelve el idioma sin región: "en-US" y "en_us" → "en"
+func base(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// ResolveHour convierte una hora de 12h acompañada de un periodo a formato
+// 24h ("3" "pm" → 15, "12" "de la noche" → 0). Devuelve false si la hora no
+// corresponde al periodo ("15 de la mañana").
+func (v *Vocabulary) ResolveHour(period string, hour int) (int, bool) {
+	for _, p := range v.Periods {
+		if p.Word != period {
+			continue
+		}
+		if p.From == p.To {
+			return hour, hour >= 0 && hour <= 23
+		}
+
+		candidatas := []int{hour}
+		if hour <= 12 {
+			candidatas = []int{hour % 12, hour%12 + 12}
+		}
+		for _, h := range candidatas {
+			if p.abarca(h) {
+				return h, true
+			}
+		}
+		return 0, false
+	}
+	return hour, hour >= 0 && hour <= 23
+}
+
+// OvernightPeriod indica si el periodo cruza la medianoche ("de la noche",
+// de 19 a 5): "las 12 de la noche" es el final del día, no el comienzo
+func (v *Vocabulary) OvernightPeriod(period string) bool {
+	for _, p := range v.Periods {
+		if p.Word == period {
+			return p.From > p.To
+		}
+	}
+	return false
+}
+
+// abarca indica si la hora (formato 24h) está dentro del periodo
+func (p Period) abarca(hour int) bool {
+	if p.From < p.To {
+		return hour >= p.From && hour < p.To
+	}
+	return hour >= p.From || hour < p.To
+}
+
+// FixedTimeWords devuelve todas las expresiones de hora fija
+func (v *Vocabulary) FixedTimeWords() []string {
+	words := make([]string, len(v.FixedTimes))
+	for i, f := range v.FixedTimes {
+		words[i] = f.Word
+	}
+	return words
+}
+
+// FixedTimeOf devuelve la hora y los minutos de una expresión de hora fija
+func (v *Vocabulary) FixedTimeOf(word string) (hour, minute int, ok bool) {
+	for _, f := range v.FixedTimes {
+		if f.Word == word {
+			return f.Hour, f.Minute, true
+		}
+	}
+	return 0, 0, false
+}
+
+// FractionWords devuelve todas las fracciones de hora
+func (v *Vocabulary) FractionWords() []string {
+	words := make([]string, len(v.Fractions))
+	for i, f := range v.Fractions {
+		words[i] = f.Word
+	}
+	return words
+}
+
+// FractionMinutes devuelve los minutos de una fracción de hora
+func (v *Vocabulary) FractionMinutes(word string) (int, bool) {
+	for _, f := range v.Fractions {
+		if f.Word == word {
+			return f.Minutes, true
+		}
+	}
+	return 0, false
+}
+
+// MonthFirst indica si en las fechas numéricas el mes va antes del día
+// ("03/15" en inglés)
+func (v *Vocabulary) MonthFirst() bool {
+	return v.DateOrder == DateOrderMDY
+}
+
+// NumberWords devuelve todas las palabras que nombran números
+func (v *Vocabulary) NumberWords() []string {
+	words := make([]string, len(v.Numbers))
+	for i, n := range v.Numbers {
+		words[i] = n.Word
+	}
+	return words
+}
+
+// NumberOf devuelve el número que nombra una palabra
+func (v *Vocabulary) NumberOf(word string) (Number, bool) {
+	for _, n := range v.Numbers {
+		if n.Word == word {
+			return n, true
+		}
+	}
+	return Number{}, false
+}
+
+// DurationUnitWords devuelve todas las unidades de duración
+func (v *Vocabulary) DurationUnitWords() []string {
+	words := make([]string, len(v.DurationUnits))
+	for i, d := range v.DurationUnits {
+		words[i] = d.Word
+	}
+	return words
+}
+
+// DurationMinutes devuelve los minutos de una unidad de duración
+func (v *Vocabulary) DurationMinutes(word string) (int, bool) {
+	for _, d := range v.DurationUnits {
+		if d.Word == word {
+			return d.Minutes, true
+		}
+	}
+	return 0, false
+}
+
+// IsArticle indica si la palabra es un artículo o un posesivo que puede
+// preceder al lugar o a la persona ("la oficina", "mi hermano")
+func (v *Vocabulary) IsArticle(word string) bool {
+	return contiene(v.Articles, strings.ToLower(word))
+}
+
+// IsPlaceMarker indica si la palabra comienza el lugar cuando le sigue un
+// artículo o un nombre propio ("at the office", "at Starbucks"), aunque
+// también comience una hora ("at 5 pm")
+func (v *Vocabulary) IsPlaceMarker(word string) bool {
+	return contiene(v.PlaceMarkers, strings.ToLower(word))
+}
+
+// IsMeasure indica si la palabra es una unidad de medida, que convierte una
+// fecha numérica en una cantidad ("1/2 kilo", "3/4 cup")
+func (v *Vocabulary) IsMeasure(word string) bool {
+	return contiene(v.Measures, strings.ToLower(word))
+}
+
+func contiene(lista []string, s string) bool {
+	for _, item := range lista {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package vocab
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	casos := []struct {
+		nombre string
+		json   string
+		error  string // parte del mensaje de error; vacío si es válido
+	}{
+		{"mínimo", `{"actionTypes": ["evento"], "verbs": [{"word": "agendá", "type": "evento"}]}`, ""},
+		{"sin verbos", `{"actionTypes": ["evento"]}`, "al menos un verbo"},
+		{"sin tipos", `{"verbs": [{"word": "agendá", "type": "evento"}]}`, "al menos un tipo"},
+		{"tipo desconocido", `{"actionTypes": ["evento"], "verbs": [{"word": "agendá", "type": "cita"}]}`, "no está en actionTypes"},
+		{"unidad inválida", `{"actionTypes": ["evento"], "verbs": [{"word": "agendá", "type": "evento"}],
+			"relativeDates": [{"word": "mañana", "amount": 1, "unit": "hora"}]}`, "unidad inválida"},
+		{"mes inválido", `{"actionTypes": ["evento"], "verbs": [{"word": "agendá", "type": "evento"}],
+			"months": [{"word": "enero", "month": 13}]}`, "mes inválido"},
+		{"medida vacía", `{"actionTypes": ["evento"], "verbs": [{"word": "agendá", "type": "evento"}],
+			"measures": [" "]}`, "unidad de medida vacía"},
+		{"JSON inválido", `{"verbs": [`, "unexpected end"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			v, err := Parse([]byte(c.json))
+			if c.error == "" {
+				if err != nil {
+					t.Fatalf("error inesperado: %v", err)
+				}
+				if v.Locale != DefaultLocale {
+					t.Errorf("locale = %q, se esperaba %q", v.Locale, DefaultLocale)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.error) {
+				t.Errorf("error = %v, se esperaba %q", err, c.error)
+			}
+		})
+	}
+}
+
+func TestEmbebidosValidos(t *testing.T) {
+	for _, locale := range Locales() {
+		if err := Get(locale).Validate(); err != nil {
+			t.Errorf("vocabulario %s: %v", locale, err)
+		}
+	}
+	if err := Default().Validate(); err != nil {
+		t.Errorf("vocabulario por defecto: %v", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Cleanup(func() { Set(Default()) })
+
+	path := filepath.Join(t.TempDir(), "vocabulario.json")
+	datos := `{"actionTypes": ["evento"], "verbs": [{"word": "apuntá", "type": "evento"}]}`
+	if err := os.WriteFile(path, []byte(datos), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if tipo, ok := Current().VerbType("apuntá"); !ok || tipo != "evento" {
+		t.Errorf("VerbType(apuntá) = %q, %v", tipo, ok)
+	}
+	if _, ok := Current().VerbType("agendá"); ok {
+		t.Error("el vocabulario cargado debe reemplazar al embebido")
+	}
+	if _, ok := Default().VerbType("agendá"); !ok {
+		t.Error("Default debe devolver el vocabulario embebido")
+	}
+
+	if err := LoadFile(filepath.Join(t.TempDir(), "no-existe.json")); err == nil {
+		t.Error("se esperaba un error al leer un archivo inexistente")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	casos := []struct {
+		preferencias []string
+		locale       string
+	}{
+		{[]string{"en-US,en;q=0.9"}, "en"},
+		{[]string{"es-AR"}, "es"},
+		{[]string{"fr-FR"}, DefaultLocale},
+		{[]string{"fr-FR, en;q=0.5"}, "en"},
+		{nil, DefaultLocale},
+		{[]string{"no es un idioma;;"}, DefaultLocale},
+	}
+
+	for _, c := range casos {
+		if got := Match(c.preferencias...); got != c.locale {
+			t.Errorf("Match(%q) = %q, se esperaba %q", c.preferencias, got, c.locale)
+		}
+	}
+
+	if Get("en-GB").Locale != "en" || Get("pt").Locale != DefaultLocale {
+		t.Errorf("Get no elige el idioma base o el idioma por defecto")
+	}
+	if _, ok := Supported("pt"); ok {
+		t.Error("pt no debe estar disponible")
+	}
+}
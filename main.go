@@ -4,8 +4,10 @@ import (
 	"log"
 	"net/http"
 	"os"
+	_ "time/tzdata" // zonas horarias de los usuarios aunque el sistema no tenga la base IANA
 
 	"github.com/RodrigoGonzalez78/go_analyzer/db"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
 	// "github.com/RodrigoGonzalez78/go_analyzer/analyzer"
 	"github.com/RodrigoGonzalez78/go_analyzer/middleware"
 	"github.com/RodrigoGonzalez78/go_analyzer/routes"
@@ -39,6 +41,14 @@ func corsMiddleware(next http.Handler) http.Handler {
 
 func main() {
 
+	// Cargar un vocabulario propio si se indicó uno; si no, se usa el embebido
+	if path := os.Getenv("VOCABULARY_FILE"); path != "" {
+		if err := vocab.LoadFile(path); err != nil {
+			log.Fatalf("Error al cargar el vocabulario: %v", err)
+		}
+		log.Printf("Vocabulario cargado desde %s\n", path)
+	}
+
 	db.StartDB()
 	db.MigrateModels()
 
@@ -56,6 +66,8 @@ func main() {
 	r.HandleFunc("GET /actions", middleware.Auth(routes.GetAllUserActions))
 	r.HandleFunc("DELETE /actions/{id}", middleware.Auth(routes.DeleteAction))
 
+	r.HandleFunc("PUT /users/me", middleware.Auth(routes.UpdateProfile))
+
 	// Configurar CORS para permitir solicitudes desde el frontend
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000"}, // Origen del frontend
@@ -3,9 +3,17 @@ package models
 import "time"
 
 type Action struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	UserName    string    `gorm:"not null;index" json:"user_name"`
-	Description string    `gorm:"not null" json:"description"`
-	Type        string    `gorm:"not null;default:'evento'" json:"type"` // "evento" o "recordatorio"
-	Date        time.Time `gorm:"not null" json:"date"`
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserName     string     `gorm:"not null;index" json:"user_name"`
+	Description  string     `gorm:"not null" json:"description"`
+	Type         string     `gorm:"not null;default:'evento'" json:"type"` // "evento", "recordatorio", "tarea" o "nota", según el verbo
+	Date         time.Time  `gorm:"not null" json:"date"`
+	EndDate      *time.Time `json:"end_date,omitempty"`                              // fin de la acción, si se indicó un rango de horas o una duración
+	Duration     int        `gorm:"not null;default:0" json:"duration,omitempty"`    // duración en minutos; 0 si no se indicó
+	Recurrence   string     `gorm:"not null;default:''" json:"recurrence,omitempty"` // DTSTART con la zona de creación y regla RRULE (RFC 5545) si la acción se repite; Date es la primera ocurrencia
+	Participants []string   `gorm:"serializer:json" json:"participants,omitempty"`   // "con Juan y Ana"
+	Location     string     `gorm:"not null;default:''" json:"location,omitempty"`   // "en la oficina"
+	Topic        string     `gorm:"not null;default:''" json:"topic,omitempty"`      // "sobre el presupuesto"
+	Priority     string     `gorm:"not null;default:''" json:"priority,omitempty"`   // "urgente", "alta", "media" o "baja"; vacío si no se indicó
+	Tags         []Tag      `gorm:"many2many:action_tags" json:"tags,omitempty"`     // "#trabajo"
 }
@@ -0,0 +1,16 @@
+package models
+
+import "encoding/json"
+
+// Tag es una etiqueta ("#trabajo") que agrupa acciones. Cada nombre se
+// guarda una sola vez y las acciones se relacionan con él en action_tags.
+type Tag struct {
+	ID   uint   `gorm:"primaryKey" json:"-"`
+	Name string `gorm:"not null;uniqueIndex" json:"name"`
+}
+
+// MarshalJSON devuelve la etiqueta como su nombre, así las acciones listan
+// "tags": ["trabajo", "salud"]
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Name)
+}
@@ -3,4 +3,8 @@ package models
 type User struct {
 	UserName string `gorm:"primaryKey" json:"user_name"`
 	Password string `gorm:"not null" json:"password"`
+	Locale   string `json:"locale"` // idioma preferido de los comandos ("es", "en"); vacío si no eligió
+
+	PastDatePolicy string `json:"pastDatePolicy"` // qué hacer con fechas pasadas: "warn" (vacío), "reject" o "roll"
+	TimeZone       string `json:"timeZone"`       // zona horaria IANA ("America/Argentina/Buenos_Aires"); vacío usa la del servidor
 }
@@ -3,74 +3,126 @@ package routes
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/RodrigoGonzalez78/go_analyzer/analyzer"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
 )
 
 type AnalyzeCommandResponse struct {
 	Success bool        `json:"success"`
 	AST     interface{} `json:"ast,omitempty"`
 	Error   interface{} `json:"error,omitempty"`
+	Errors  []*analyzer.AnalyzerError `json:"errors,omitempty"`
 	Analysis map[string]interface{} `json:"analysis,omitempty"`
 }
 
 func AnalyzeCommand(w http.ResponseWriter, r *http.Request) {
 	type Request struct {
 		Command string `json:"command"`
+		AutoCorrect bool `json:"autocorrect"`
+		Locale string `json:"locale"`
+		Lenient bool `json:"lenient"`
+		TimeZone string `json:"timeZone"`
+		Now *time.Time `json:"now"` // hora de referencia (RFC 3339) para reproducir un análisis
 	}
 
 	var request Request
 
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		http.Error(w, "Error al decodificar el contenido", http.StatusBadRequest)
+		http.Error(w, i18n.T(localeEncabezado(r), "Error al decodificar el contenido"), http.StatusBadRequest)
 		return
 	}
 
+	locale, ok := resolverLocale(r, request.Locale, "")
+	if !ok {
+		http.Error(w, i18n.Sprintf(localeEncabezado(r), "Idioma no soportado: %s", request.Locale), http.StatusBadRequest)
+		return
+	}
+
+	loc, ok := resolverZona(request.TimeZone, "")
+	if !ok {
+		http.Error(w, i18n.Sprintf(locale, "Zona horaria no soportada: %s", request.TimeZone), http.StatusBadRequest)
+		return
+	}
+	clock := analyzer.SystemClock
+	if request.Now != nil {
+		clock = analyzer.FixedClock(*request.Now)
+		if request.TimeZone == "" {
+			// Sin zona explícita vale el desplazamiento de now
+			loc = request.Now.Location()
+		}
+	}
+
 	if request.Command == "" {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(AnalyzeCommandResponse{
 			Success: false,
 			Error: map[string]interface{}{
 				"type":    "EMPTY_COMMAND",
-				"message": "No se envió ningún comando",
+				"message": i18n.T(locale, "No se envió ningún comando"),
 				"position": 0,
 			},
 		})
 		return
 	}
 
-	parsedAction, analyzeErr := analyzer.CreateAction(request.Command)
-	if analyzeErr != nil {
+	result := analyzer.AnalyzeWithOptions(request.Command, analyzer.Options{AutoCorrect: request.AutoCorrect, Locale: locale, Lenient: request.Lenient})
+	comando, analyzeErrs := result.Comando, result.Errors
+	if len(analyzeErrs) > 0 {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(AnalyzeCommandResponse{
 			Success: false,
-			Error: map[string]interface{}{
-				"type":    "SYNTAX_ERROR",
-				"message": analyzeErr.Error(),
-				"position": 0,
-			},
+			Error:   analyzeErrs[0], // *AnalyzerError con código, posición y tokens esperados
+			Errors:  analyzeErrs,
 		})
 		return
 	}
 
-	ast := buildAST(request.Command, parsedAction)
+	tree := buildAST(request.Command, comando)
 	
 	// Crear información del análisis
-	analysis := map[string]interface{}{
-		"command": request.Command,
-		"verb": parsedAction.Verbo,
-		"words": parsedAction.Palabras,
-		"date": parsedAction.Fecha,
-		"time": parsedAction.Hora,
-		"description": strings.Join(parsedAction.Palabras, " "),
+	analysis := buildAnalysis(request.Command, comando)
+	if len(result.Corrections) > 0 {
+		analysis["corrections"] = result.Corrections
+	}
+	if len(result.Normalizations) > 0 {
+		analysis["normalizations"] = result.Normalizations
 	}
+	if len(result.Warnings) > 0 {
+		analysis["suggestions"] = result.Warnings
+	}
+	resolverFecha(analysis, comando, analyzer.TransformOptions{Location: loc, Clock: clock})
 	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(AnalyzeCommandResponse{
 		Success: true,
-		AST:     ast,
+		AST:     tree,
 		Analysis: analysis,
 	})
-} 
\ No newline at end of file
+}
+
+// resolverFecha agrega al análisis la fecha concreta que tendría la acción,
+// con la hora de referencia y la zona indicadas, la fecha de fin si tiene
+// duración, la regla RRULE si se repite y las advertencias sobre fechas
+// pasadas
+func resolverFecha(analysis map[string]interface{}, comando *ast.Comando, opts analyzer.TransformOptions) {
+	action, warnings, err := analyzer.TransformToActionWithOptions(comando, "", opts)
+	if err != nil {
+		analysis["resolveError"] = err.Error()
+		return
+	}
+	analysis["now"] = opts.Clock.Now().In(opts.Location).Format(time.RFC3339)
+	analysis["resolvedDate"] = action.Date.In(opts.Location).Format(time.RFC3339)
+	if action.EndDate != nil {
+		analysis["resolvedEndDate"] = action.EndDate.In(opts.Location).Format(time.RFC3339)
+	}
+	if action.Recurrence != "" {
+		analysis["rrule"] = action.Recurrence
+	}
+	if len(warnings) > 0 {
+		analysis["warnings"] = warnings
+	}
+}
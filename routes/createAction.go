@@ -2,11 +2,16 @@ package routes
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/RodrigoGonzalez78/go_analyzer/analyzer"
 	"github.com/RodrigoGonzalez78/go_analyzer/db"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/ast"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
 	"github.com/RodrigoGonzalez78/go_analyzer/models"
 )
 
@@ -14,7 +19,23 @@ type CreateActionResponse struct {
 	Success bool        `json:"success"`
 	AST     interface{} `json:"ast,omitempty"`
 	Error   interface{} `json:"error,omitempty"`
+	Errors  []*analyzer.AnalyzerError `json:"errors,omitempty"`
 	Analysis map[string]interface{} `json:"analysis,omitempty"`
+	Warnings []analyzer.Warning `json:"warnings,omitempty"`
+	Results []CreateActionResult `json:"results,omitempty"`
+}
+
+// CreateActionResult es el resultado de cada comando cuando se crean varias
+// acciones a la vez
+type CreateActionResult struct {
+	Command  string                    `json:"command"`
+	Success  bool                      `json:"success"`
+	ID       uint                      `json:"id,omitempty"`
+	AST      interface{}               `json:"ast,omitempty"`
+	Error    interface{}               `json:"error,omitempty"`
+	Errors   []*analyzer.AnalyzerError `json:"errors,omitempty"`
+	Analysis map[string]interface{}    `json:"analysis,omitempty"`
+	Warnings []analyzer.Warning        `json:"warnings,omitempty"`
 }
 
 func CreateAction(w http.ResponseWriter, r *http.Request) {
@@ -22,41 +43,73 @@ func CreateAction(w http.ResponseWriter, r *http.Request) {
 
 	type Request struct {
 		Comand string `json:"comand"`
+		Comands []string `json:"comands"`
+		AutoCorrect bool `json:"autocorrect"`
+		Locale string `json:"locale"`
+		Lenient bool `json:"lenient"`
+		TimeZone string `json:"timeZone"`
 	}
 
 	var comand Request
 
 	err := json.NewDecoder(r.Body).Decode(&comand)
 	if err != nil {
-		http.Error(w, "Error al decodificar el contenido", http.StatusBadRequest)
+		http.Error(w, i18n.T(localeEncabezado(r), "Error al decodificar el contenido"), http.StatusBadRequest)
+		return
+	}
+
+	locale, ok := resolverLocale(r, comand.Locale, claim.UserName)
+	if !ok {
+		http.Error(w, i18n.Sprintf(localeEncabezado(r), "Idioma no soportado: %s", comand.Locale), http.StatusBadRequest)
+		return
+	}
+
+	loc, ok := resolverZona(comand.TimeZone, claim.UserName)
+	if !ok {
+		http.Error(w, i18n.Sprintf(locale, "Zona horaria no soportada: %s", comand.TimeZone), http.StatusBadRequest)
 		return
 	}
 
-	if comand.Comand == "" {
-		http.Error(w, "No se envio ningun comando", http.StatusBadRequest)
+	entradas := comand.Comands
+	if comand.Comand != "" {
+		entradas = append([]string{comand.Comand}, entradas...)
+	}
+	if len(entradas) == 0 {
+		http.Error(w, i18n.T(locale, "No se envió ningún comando"), http.StatusBadRequest)
 		return
 	}
 
-	parsedAction, analyzeErr := analyzer.CreateAction(comand.Comand)
-	if analyzeErr != nil {
+	// Cada entrada puede tener varios comandos ("agendá dentista el lunes y
+	// recordame comprar pan el martes")
+	opts := analyzer.Options{AutoCorrect: comand.AutoCorrect, Locale: locale, Lenient: comand.Lenient}
+	var results []analyzer.Result
+	for _, entrada := range entradas {
+		results = append(results, analyzer.AnalyzeBatch(entrada, opts)...)
+	}
+	transform := analyzer.TransformOptions{PastDates: politicaFechasPasadas(claim.UserName), Location: loc}
+	if len(comand.Comands) > 0 || len(results) > 1 {
+		crearAcciones(w, claim.UserName, locale, results, transform)
+		return
+	}
+
+	result := results[0]
+	comando, analyzeErrs := result.Comando, result.Errors
+	if len(analyzeErrs) > 0 {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(CreateActionResponse{
 			Success: false,
-			Error:   analyzeErr.Error(), // ya es *AnalyzerError
+			Error:   analyzeErrs[0], // ya es *AnalyzerError
+			Errors:  analyzeErrs,
 		})
 		return
 	}
 
-	action, err := analyzer.TransformToAction(parsedAction, claim.UserName)
+	action, warnings, err := analyzer.TransformToActionWithOptions(comando, claim.UserName, transform)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(CreateActionResponse{
 			Success: false,
-			Error: map[string]interface{}{
-				"type":    "TRANSFORM_ERROR",
-				"message": err.Error(),
-				"position": 0,
-			},
+			Error:   errorTransformacion(err),
 		})
 		return
 	}
@@ -65,92 +118,361 @@ func CreateAction(w http.ResponseWriter, r *http.Request) {
 
 	err = db.CreateAction(action)
 	if err != nil {
-		http.Error(w, "Error creando la accion", http.StatusInternalServerError)
+		http.Error(w, i18n.T(locale, "Error creando la accion"), http.StatusInternalServerError)
 		return
 	}
 
-	ast := buildAST(comand.Comand, parsedAction)
+	tree := buildAST(comand.Comand, comando)
 	
 	// Crear información del análisis
-	analysis := map[string]interface{}{
-		"command": comand.Comand,
-		"verb": parsedAction.Verbo,
-		"words": parsedAction.Palabras,
-		"date": parsedAction.Fecha,
-		"time": parsedAction.Hora,
-		"description": strings.Join(parsedAction.Palabras, " "),
-	}
+	analysis := analisisResultado(comand.Comand, result)
 	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(CreateActionResponse{
 		Success: true,
-		AST:     ast,
+		AST:     tree,
 		Analysis: analysis,
+		Warnings: warnings,
+	})
+}
+
+// crearAcciones crea las acciones de varios comandos en una sola
+// transacción y responde con el resultado de cada uno, en orden. Si algún
+// comando tiene errores no se crea ninguna acción y la respuesta es 422: los
+// demás comandos traen success false con un error ROLLED_BACK, junto con su
+// análisis, para que el cliente corrija sólo los que fallaron.
+func crearAcciones(w http.ResponseWriter, userName, locale string, results []analyzer.Result, transform analyzer.TransformOptions) {
+	items := make([]CreateActionResult, len(results))
+	var actions []models.Action
+	success := true
+	for i, result := range results {
+		items[i].Command = result.Text
+		if len(result.Errors) > 0 {
+			items[i].Error, items[i].Errors = result.Errors[0], result.Errors
+			success = false
+			continue
+		}
+
+		action, warnings, err := analyzer.TransformToActionWithOptions(result.Comando, userName, transform)
+		if err != nil {
+			items[i].Error = errorTransformacion(err)
+			success = false
+			continue
+		}
+		action.UserName = userName
+		actions = append(actions, action)
+
+		items[i].Success = true
+		items[i].AST = buildAST(result.Text, result.Comando)
+		items[i].Analysis = analisisResultado(result.Text, result)
+		items[i].Warnings = warnings
+	}
+
+	status := http.StatusOK
+	if success {
+		if err := db.CreateActions(actions); err != nil {
+			http.Error(w, i18n.T(locale, "Error creando la accion"), http.StatusInternalServerError)
+			return
+		}
+		for i := range items {
+			items[i].ID = actions[i].ID
+		}
+	} else {
+		status = http.StatusUnprocessableEntity
+		for i := range items {
+			if items[i].Success {
+				items[i].Success = false
+				items[i].Error = errorDeshecho(locale)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(CreateActionResponse{
+		Success: success,
+		Results: items,
 	})
 }
 
-// buildAST igual que en analyzeCommand.go
-func buildAST(command string, parsed analyzer.ParsedAction) map[string]interface{} {
+// errorTransformacion describe un error al convertir el comando en acción
+func errorTransformacion(err error) map[string]interface{} {
+	tipo := "TRANSFORM_ERROR"
+	var pasada *analyzer.PastDateError
+	if errors.As(err, &pasada) {
+		tipo = analyzer.CodigoFechaPasada
+	}
+	return map[string]interface{}{
+		"type":     tipo,
+		"message":  err.Error(),
+		"position": 0,
+	}
+}
+
+// errorDeshecho describe un comando válido que no se creó porque otro comando
+// del mismo pedido tiene errores
+func errorDeshecho(locale string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "ROLLED_BACK",
+		"message":  i18n.T(locale, "No se creó: otro comando tiene errores"),
+		"position": 0,
+	}
+}
+
+// analisisResultado resume el comando analizado junto con las correcciones y
+// ajustes que se le aplicaron
+func analisisResultado(command string, result analyzer.Result) map[string]interface{} {
+	analysis := buildAnalysis(command, result.Comando)
+	if len(result.Corrections) > 0 {
+		analysis["corrections"] = result.Corrections
+	}
+	if len(result.Normalizations) > 0 {
+		analysis["normalizations"] = result.Normalizations
+	}
+	if len(result.Warnings) > 0 {
+		analysis["suggestions"] = result.Warnings
+	}
+	return analysis
+}
+
+// buildAnalysis resume el comando analizado para la respuesta JSON
+func buildAnalysis(command string, comando *ast.Comando) map[string]interface{} {
+	verbo, _ := comando.Verbo.(*ast.Verbo)
+	detalle, _ := comando.Detalle.(*ast.DetalleEvento)
+	tiempo, _ := comando.Tiempo.(*ast.Tiempo)
+
+	fecha, hora, horaFin, duracion, recurrencia := "", "", "", "", ""
+	if tiempo.Fecha != nil {
+		fecha = tiempo.Fecha.String()
+	}
+	if tiempo.Hora != nil {
+		hora = tiempo.Hora.String()
+	}
+	if tiempo.HoraFin != nil {
+		horaFin = tiempo.HoraFin.String()
+	}
+	if tiempo.Duracion != nil {
+		duracion = tiempo.Duracion.String()
+	}
+	if tiempo.Recurrencia != nil {
+		recurrencia = tiempo.Recurrencia.String()
+	}
+
+	return map[string]interface{}{
+		"command": command,
+		"verb": verbo.Value,
+		"type": analyzer.TipoVerbo(verbo),
+		"locale": comando.Locale,
+		"words": detalle.Palabras,
+		"date": fecha,
+		"time": hora,
+		"endTime": horaFin,
+		"duration": duracion,
+		"recurrence": recurrencia,
+		"description": strings.Join(detalle.Palabras, " "),
+		"participants": detalle.Participantes,
+		"location": detalle.Lugar,
+		"topic": detalle.Tema,
+		"priority": detalle.Prioridad,
+		"tags": detalle.Etiquetas,
+	}
+}
+
+// buildAST convierte el AST del analizador al árbol que consume el frontend
+func buildAST(command string, comando *ast.Comando) map[string]interface{} {
+	vocabulario := vocab.Get(comando.Locale)
+	verbo, _ := comando.Verbo.(*ast.Verbo)
+	detalle, _ := comando.Detalle.(*ast.DetalleEvento)
+	tiempo, _ := comando.Tiempo.(*ast.Tiempo)
+
+	palabrasNode := map[string]interface{}{
+		"name": "PALABRAS",
+		"attributes": map[string]interface{}{
+			"value": detalle.Palabras,
+			"count": len(detalle.Palabras),
+		},
+	}
+	if detalle.TipoEvento != "" {
+		palabrasNode["attributes"].(map[string]interface{})["eventType"] = detalle.TipoEvento
+		palabrasNode["attributes"].(map[string]interface{})["validEventTypes"] = vocabulario.EventTypes
+	}
+	if detalle.Nombre != "" {
+		palabrasNode["attributes"].(map[string]interface{})["with"] = detalle.Nombre
+	}
+
 	root := map[string]interface{}{
 		"name": "COMANDO",
 		"attributes": map[string]interface{}{
 			"value": command,
+			"locale": comando.Locale,
 		},
 		"children": []map[string]interface{}{
 			{
 				"name": "VERBO",
 				"attributes": map[string]interface{}{
-					"value": parsed.Verbo,
-					"validVerbs": []string{"agendá", "anotá", "recordame"},
-				},
-			},
-			{
-				"name": "PALABRAS",
-				"attributes": map[string]interface{}{
-					"value": parsed.Palabras,
-					"count": len(parsed.Palabras),
+					"value": verbo.Value,
+					"actionType": analyzer.TipoVerbo(verbo),
+					"validVerbs": vocabulario.VerbWords(),
+					"validTypes": vocabulario.ActionTypes,
 				},
 			},
+			palabrasNode,
 		},
 	}
 
+	// Agregar los participantes, el lugar y el tema si se indicaron
+	if len(detalle.Participantes) > 0 {
+		root["children"] = append(root["children"].([]map[string]interface{}), map[string]interface{}{
+			"name": "PARTICIPANTES",
+			"attributes": map[string]interface{}{
+				"value": detalle.Participantes,
+				"count": len(detalle.Participantes),
+			},
+		})
+	}
+	if detalle.Lugar != "" {
+		root["children"] = append(root["children"].([]map[string]interface{}), map[string]interface{}{
+			"name": "LUGAR",
+			"attributes": map[string]interface{}{"value": detalle.Lugar},
+		})
+	}
+	if detalle.Tema != "" {
+		root["children"] = append(root["children"].([]map[string]interface{}), map[string]interface{}{
+			"name": "TEMA",
+			"attributes": map[string]interface{}{"value": detalle.Tema},
+		})
+	}
+
+	// Agregar la prioridad y las etiquetas si se indicaron
+	if detalle.Prioridad != "" {
+		root["children"] = append(root["children"].([]map[string]interface{}), map[string]interface{}{
+			"name": "PRIORIDAD",
+			"attributes": map[string]interface{}{
+				"value": detalle.Prioridad,
+				"validLevels": vocabulario.PriorityLevels,
+			},
+		})
+	}
+	if len(detalle.Etiquetas) > 0 {
+		root["children"] = append(root["children"].([]map[string]interface{}), map[string]interface{}{
+			"name": "ETIQUETAS",
+			"attributes": map[string]interface{}{
+				"value": detalle.Etiquetas,
+				"count": len(detalle.Etiquetas),
+			},
+		})
+	}
+
 	// Agregar nodo de tiempo si existe
-	if parsed.Fecha != "" || parsed.Hora != "" {
+	if tiempo.Fecha != nil || tiempo.Hora != nil || tiempo.Duracion != nil || tiempo.Recurrencia != nil {
 		tiempoNode := map[string]interface{}{
 			"name": "TIEMPO",
 			"children": []map[string]interface{}{},
 		}
 		
-		if parsed.Fecha != "" {
+		if tiempo.Fecha != nil {
 			tiempoNode["children"] = append(tiempoNode["children"].([]map[string]interface{}), map[string]interface{}{
 				"name": "FECHA",
-				"attributes": map[string]interface{}{
-					"value": parsed.Fecha,
-					"type": getDateType(parsed.Fecha),
-				},
+				"attributes": fechaAttributes(tiempo.Fecha),
 			})
 		}
-		if parsed.Hora != "" {
+		if tiempo.Hora != nil {
 			tiempoNode["children"] = append(tiempoNode["children"].([]map[string]interface{}), map[string]interface{}{
 				"name": "HORA",
+				"attributes": horaAttributes(comando.Locale, tiempo.Hora),
+			})
+		}
+		if tiempo.HoraFin != nil {
+			tiempoNode["children"] = append(tiempoNode["children"].([]map[string]interface{}), map[string]interface{}{
+				"name": "HORA_FIN",
+				"attributes": horaAttributes(comando.Locale, tiempo.HoraFin),
+			})
+		}
+		if tiempo.Duracion != nil {
+			tiempoNode["children"] = append(tiempoNode["children"].([]map[string]interface{}), map[string]interface{}{
+				"name": "DURACION",
 				"attributes": map[string]interface{}{
-					"value": parsed.Hora,
-					"format": "24h",
+					"value": tiempo.Duracion.String(),
+					"minutes": tiempo.Duracion.Minutos,
 				},
 			})
 		}
+		if tiempo.Recurrencia != nil {
+			tiempoNode["children"] = append(tiempoNode["children"].([]map[string]interface{}), map[string]interface{}{
+				"name": "RECURRENCIA",
+				"attributes": recurrenciaAttributes(tiempo.Recurrencia),
+			})
+		}
 		root["children"] = append(root["children"].([]map[string]interface{}), tiempoNode)
 	}
 
 	return root
 }
 
-func getDateType(fecha string) string {
-	switch fecha {
-	case "hoy", "mañana":
+// fechaAttributes describe la fecha y, si es relativa, cómo se resuelve
+func fechaAttributes(fecha *ast.Fecha) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"value": fecha.String(),
+		"type": getDateType(fecha),
+	}
+	if fecha.Desplazamiento != nil {
+		attributes["offset"] = fecha.Desplazamiento.Cantidad
+		attributes["unit"] = fecha.Desplazamiento.Unidad
+	}
+	if fecha.Modificador != "" {
+		attributes["modifier"] = fecha.Modificador
+	}
+	if fecha.Fin != nil {
+		attributes["unit"] = fecha.Fin.Unidad
+	}
+	if fecha.Tipo == "especifica" && fecha.Anio == 0 {
+		// El año (y en "el 15" el mes) se deduce como la próxima ocurrencia
+		attributes["inferred"] = true
+	}
+	return attributes
+}
+
+// horaAttributes describe la hora tal como se escribió y su valor en formato 24h
+func horaAttributes(locale string, hora *ast.Hora) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"value": hora.String(),
+		"format": "24h",
+	}
+	if hora.Periodo != "" || hora.Nombre != "" || hora.Menos != 0 {
+		attributes["format"] = "coloquial"
+	}
+	if h, m, err := analyzer.HoraCanonica(locale, hora); err == nil {
+		attributes["canonical"] = fmt.Sprintf("%02d:%02d", h, m)
+	}
+	return attributes
+}
+
+// recurrenciaAttributes describe cada cuánto se repite la acción
+func recurrenciaAttributes(recurrencia *ast.Recurrencia) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"value": recurrencia.String(),
+		"unit": recurrencia.Unidad,
+		"interval": recurrencia.Intervalo,
+	}
+	if len(recurrencia.Dias) > 0 {
+		attributes["days"] = recurrencia.Dias
+	}
+	if recurrencia.DiaDelMes != 0 {
+		attributes["monthDay"] = recurrencia.DiaDelMes
+	}
+	return attributes
+}
+
+func getDateType(fecha *ast.Fecha) string {
+	switch fecha.Tipo {
+	case "relativa":
 		return "relativa"
-	case "lunes", "martes", "miércoles", "jueves", "viernes", "sábado", "domingo":
+	case "diasemana":
 		return "día_semana"
+	case "desplazamiento":
+		return "desplazamiento"
+	case "fin":
+		return "fin_de_periodo"
 	default:
 		return "específica"
 	}
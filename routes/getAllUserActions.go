@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/RodrigoGonzalez78/go_analyzer/db"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
 	"github.com/RodrigoGonzalez78/go_analyzer/models"
 )
 
@@ -32,12 +35,57 @@ func GetAllUserActions(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	actions, err := db.GetUserActionsPaginated(claim.UserName, page, pageSize)
+	// Las fechas se guardan en UTC y se devuelven en la zona del usuario
+	loc, ok := resolverZona(query.Get("timeZone"), claim.UserName)
+	if !ok {
+		http.Error(w, i18n.Sprintf(localeEncabezado(r), "Zona horaria no soportada: %s", query.Get("timeZone")), http.StatusBadRequest)
+		return
+	}
+
+	// Filtros por etiqueta ("trabajo" o "#trabajo") y nivel de prioridad
+	filtro := db.ActionFilter{
+		Tag: strings.ToLower(strings.TrimPrefix(query.Get("tag"), "#")),
+	}
+	if query.Get("priority") != "" {
+		locale, _ := resolverLocale(r, "", claim.UserName)
+		nivel, ok := nivelPrioridad(vocab.Get(locale), query.Get("priority"))
+		if !ok {
+			http.Error(w, i18n.Sprintf(locale, "Prioridad no soportada: %s", query.Get("priority")), http.StatusBadRequest)
+			return
+		}
+		filtro.Priority = nivel
+	}
+
+	// Con un rango de fechas se listan las ocurrencias de las acciones recurrentes
+	if query.Has("from") || query.Has("to") {
+		listarOcurrencias(w, r, claim.UserName, loc, page, pageSize, filtro)
+		return
+	}
+
+	actions, err := db.GetUserActionsPaginated(claim.UserName, page, pageSize, filtro)
 	if err != nil {
 		http.Error(w, "Error al obtener las acciones", http.StatusInternalServerError)
 		return
 	}
 
+	for i := range actions {
+		actions[i].Date = actions[i].Date.In(loc)
+		if actions[i].EndDate != nil {
+			fin := actions[i].EndDate.In(loc)
+			actions[i].EndDate = &fin
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(actions)
 }
+
+// nivelPrioridad normaliza el filtro de prioridad con el vocabulario del
+// usuario: acepta un nivel ("alta") o una palabra que lo asigna ("important")
+func nivelPrioridad(vocabulario *vocab.Vocabulary, valor string) (string, bool) {
+	valor = strings.ToLower(strings.TrimSpace(valor))
+	if vocabulario.IsPriorityLevel(valor) {
+		return valor, true
+	}
+	return vocabulario.PriorityOf(valor)
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/db"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
+)
+
+// resolverLocale elige el idioma de un comando. En orden de prioridad: el
+// campo locale del pedido, el idioma del perfil del usuario (si está
+// autenticado) y el encabezado Accept-Language. Devuelve false si el pedido
+// indica explícitamente un idioma no soportado.
+func resolverLocale(r *http.Request, pedido, userName string) (string, bool) {
+	if pedido != "" {
+		return vocab.Supported(pedido)
+	}
+
+	if userName != "" {
+		user, err := db.GetUserByUserName(userName)
+		if err == nil && user != nil && user.Locale != "" {
+			if locale, ok := vocab.Supported(user.Locale); ok {
+				return locale, true
+			}
+		}
+	}
+
+	return localeEncabezado(r), true
+}
+
+// localeEncabezado elige el idioma según el encabezado Accept-Language, para
+// los mensajes que se responden antes de leer el pedido
+func localeEncabezado(r *http.Request) string {
+	return vocab.Match(r.Header.Get("Accept-Language"))
+}
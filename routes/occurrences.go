@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/analyzer"
+	"github.com/RodrigoGonzalez78/go_analyzer/db"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/models"
+)
+
+// rangoMaximoDias limita el rango que se expande al listar las ocurrencias
+const rangoMaximoDias = 366
+
+// formatoDiaConsulta es el formato de las fechas sin hora en from y to
+const formatoDiaConsulta = "2006-01-02"
+
+// listarOcurrencias responde las acciones del usuario entre from y to, con
+// las acciones recurrentes expandidas en cada ocurrencia, ordenadas por fecha
+// y paginadas
+func listarOcurrencias(w http.ResponseWriter, r *http.Request, userName string, loc *time.Location, page, pageSize int, filtro db.ActionFilter) {
+	query := r.URL.Query()
+	locale := localeEncabezado(r)
+
+	if query.Get("from") == "" || query.Get("to") == "" {
+		http.Error(w, i18n.T(locale, "Los parámetros from y to van juntos"), http.StatusBadRequest)
+		return
+	}
+	desde, err := parsearLimite(query.Get("from"), loc, false)
+	if err != nil {
+		http.Error(w, i18n.Sprintf(locale, "Fecha inválida en %s: %s", "from", query.Get("from")), http.StatusBadRequest)
+		return
+	}
+	hasta, err := parsearLimite(query.Get("to"), loc, true)
+	if err != nil {
+		http.Error(w, i18n.Sprintf(locale, "Fecha inválida en %s: %s", "to", query.Get("to")), http.StatusBadRequest)
+		return
+	}
+	if hasta.Sub(desde) > rangoMaximoDias*24*time.Hour {
+		http.Error(w, i18n.Sprintf(locale, "El rango de fechas no puede superar %d días", rangoMaximoDias), http.StatusBadRequest)
+		return
+	}
+
+	actions, err := db.GetUserActionsBetween(userName, desde, hasta, filtro)
+	if err != nil {
+		http.Error(w, "Error al obtener las acciones", http.StatusInternalServerError)
+		return
+	}
+
+	ocurrencias := []models.Action{}
+	for _, action := range actions {
+		expandidas, err := analyzer.Occurrences(action, desde, hasta, loc)
+		if err != nil {
+			// Una regla que no se puede leer no impide listar las demás
+			continue
+		}
+		ocurrencias = append(ocurrencias, expandidas...)
+	}
+	sort.SliceStable(ocurrencias, func(i, j int) bool {
+		return ocurrencias[i].Date.Before(ocurrencias[j].Date)
+	})
+
+	inicio := min((page-1)*pageSize, len(ocurrencias))
+	fin := min(inicio+pageSize, len(ocurrencias))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ocurrencias[inicio:fin])
+}
+
+// parsearLimite lee un extremo del rango: una fecha y hora RFC 3339 o un día
+// "2006-01-02" en la zona del usuario. Si fin es true el día se incluye
+// completo, así "to=2025-06-30" abarca hasta el final del 30.
+func parsearLimite(valor string, loc *time.Location, fin bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, valor); err == nil {
+		return t, nil
+	}
+	dia, err := time.ParseInLocation(formatoDiaConsulta, valor, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if fin {
+		dia = dia.AddDate(0, 0, 1)
+	}
+	return dia, nil
+}
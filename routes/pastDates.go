@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/RodrigoGonzalez78/go_analyzer/analyzer"
+	"github.com/RodrigoGonzalez78/go_analyzer/db"
+)
+
+// politicaFechasPasadas devuelve la política de fechas pasadas elegida por
+// el usuario en su perfil, o la política por defecto
+func politicaFechasPasadas(userName string) analyzer.PastDatePolicy {
+	user, err := db.GetUserByUserName(userName)
+	if err != nil || user == nil {
+		return analyzer.PastDatesWarn
+	}
+	politica, ok := analyzer.ParsePastDatePolicy(user.PastDatePolicy)
+	if !ok {
+		return analyzer.PastDatesWarn
+	}
+	return politica
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/RodrigoGonzalez78/go_analyzer/analyzer"
 	"github.com/RodrigoGonzalez78/go_analyzer/db"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/vocab"
 	"github.com/RodrigoGonzalez78/go_analyzer/models"
 	"github.com/RodrigoGonzalez78/go_analyzer/utils"
 )
@@ -30,6 +32,25 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if t.Locale != "" {
+		locale, ok := vocab.Supported(t.Locale)
+		if !ok {
+			http.Error(w, "Idioma no soportado: "+t.Locale, 400)
+			return
+		}
+		t.Locale = locale
+	}
+
+	if t.TimeZone != "" && !zonaValida(t.TimeZone) {
+		http.Error(w, "Zona horaria no soportada: "+t.TimeZone, 400)
+		return
+	}
+
+	if _, ok := analyzer.ParsePastDatePolicy(t.PastDatePolicy); !ok {
+		http.Error(w, "Política de fechas pasadas no soportada: "+t.PastDatePolicy, 400)
+		return
+	}
+
 	encrypt_password, err := utils.GenerateHashPassword(t.Password)
 
 	if err != nil {
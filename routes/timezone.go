@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/db"
+)
+
+// resolverZona elige la zona horaria con que se interpretan y devuelven las
+// fechas. En orden de prioridad: la zona del pedido, la del perfil del
+// usuario y la del servidor. Devuelve false si el pedido indica una zona que
+// no existe.
+func resolverZona(pedido, userName string) (*time.Location, bool) {
+	if pedido != "" {
+		loc, err := time.LoadLocation(pedido)
+		return loc, err == nil
+	}
+
+	if userName != "" {
+		user, err := db.GetUserByUserName(userName)
+		if err == nil && user != nil && user.TimeZone != "" {
+			if loc, err := time.LoadLocation(user.TimeZone); err == nil {
+				return loc, true
+			}
+		}
+	}
+
+	return time.Local, true
+}
+
+// zonaValida indica si el nombre es una zona horaria IANA conocida
+func zonaValida(nombre string) bool {
+	_, err := time.LoadLocation(nombre)
+	return err == nil
+}
@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/RodrigoGonzalez78/go_analyzer/analyzer"
+	"github.com/RodrigoGonzalez78/go_analyzer/db"
+	"github.com/RodrigoGonzalez78/go_analyzer/internal/i18n"
+	"github.com/RodrigoGonzalez78/go_analyzer/models"
+)
+
+// UpdateProfile actualiza las preferencias del usuario autenticado. Sólo se
+// modifican los campos enviados.
+func UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	claim, _ := r.Context().Value("userData").(*models.Claim)
+
+	type Request struct {
+		Locale         *string `json:"locale"`
+		PastDatePolicy *string `json:"pastDatePolicy"`
+		TimeZone       *string `json:"timeZone"`
+	}
+
+	var request Request
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, i18n.T(localeEncabezado(r), "Error al decodificar el contenido"), http.StatusBadRequest)
+		return
+	}
+
+	campos := map[string]interface{}{}
+
+	if request.Locale != nil {
+		locale := ""
+		if *request.Locale != "" {
+			var ok bool
+			locale, ok = resolverLocale(r, *request.Locale, "")
+			if !ok {
+				http.Error(w, i18n.Sprintf(localeEncabezado(r), "Idioma no soportado: %s", *request.Locale), http.StatusBadRequest)
+				return
+			}
+		}
+		campos["locale"] = locale
+	}
+
+	if request.PastDatePolicy != nil {
+		if _, ok := analyzer.ParsePastDatePolicy(*request.PastDatePolicy); !ok {
+			http.Error(w, i18n.Sprintf(localeEncabezado(r), "Política de fechas pasadas no soportada: %s", *request.PastDatePolicy), http.StatusBadRequest)
+			return
+		}
+		campos["past_date_policy"] = *request.PastDatePolicy
+	}
+
+	if request.TimeZone != nil {
+		if *request.TimeZone != "" && !zonaValida(*request.TimeZone) {
+			http.Error(w, i18n.Sprintf(localeEncabezado(r), "Zona horaria no soportada: %s", *request.TimeZone), http.StatusBadRequest)
+			return
+		}
+		campos["time_zone"] = *request.TimeZone
+	}
+
+	if len(campos) > 0 {
+		err = db.UpdateUserProfile(claim.UserName, campos)
+		if err != nil {
+			http.Error(w, "Error al actualizar el perfil: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}